@@ -1,17 +1,21 @@
 package feishu_bot_api
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
 	"path"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/electricbubble/xhttpclient"
-	"golang.org/x/time/rate"
+	"github.com/electricbubble/feishu-bot-api/v2/clock"
 )
 
 type Bot interface {
@@ -21,12 +25,16 @@ type Bot interface {
 	//
 	// @指定人: TextAtPerson
 	// @所有人: TextAtEveryone
+	//
+	// 内容来自外部输入时建议使用 TextBuilder 构建（转义 & < >），并通过 SendMessage 发送
 	SendText(content string) error
+	SendTextContext(ctx context.Context, content string) error
 
 	// SendRichText 发送富文本消息
 	//
 	// https://open.feishu.cn/document/client-docs/bot-v3/add-custom-bot?lang=zh-CN#f62e72d5
 	SendRichText(rt *RichTextBuilder, multiLanguage ...*RichTextBuilder) error
+	SendRichTextContext(ctx context.Context, rt *RichTextBuilder, multiLanguage ...*RichTextBuilder) error
 
 	// SendGroupBusinessCard 发送群名片
 	//
@@ -34,6 +42,7 @@ type Bot interface {
 	//
 	// 群 ID 获取方式: https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/chat-id-description
 	SendGroupBusinessCard(chatID string) error
+	SendGroupBusinessCardContext(ctx context.Context, chatID string) error
 
 	// SendImage 发送图片
 	//
@@ -41,18 +50,37 @@ type Bot interface {
 	//
 	// image_key 获取方式: https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/image/create
 	SendImage(imgKey string) error
+	SendImageContext(ctx context.Context, imgKey string) error
 
 	// SendCard 发送消息卡片
 	//
 	// https://open.feishu.cn/document/client-docs/bot-v3/add-custom-bot?lang=zh-CN#4996824a
 	SendCard(globalConf *CardGlobalConfig, card *CardBuilder, multiLanguage ...*CardBuilder) error
+	SendCardContext(ctx context.Context, globalConf *CardGlobalConfig, card *CardBuilder, multiLanguage ...*CardBuilder) error
+
+	// SendCardV2 发送卡片 JSON 2.0 结构的消息卡片
+	//
+	// https://open.feishu.cn/document/uAjLw4CM/ukzMukzMukzM/feishu-cards/card-json-v2-structure
+	SendCardV2(card *CardV2Builder) error
+	SendCardV2Context(ctx context.Context, card *CardV2Builder) error
 
 	// SendCardViaTemplate 使用卡片 ID 发送消息
 	//
 	// https://open.feishu.cn/document/ukTMukTMukTM/uYzM3QjL2MzN04iNzcDN/send-message-card/send-message-using-card-id
 	SendCardViaTemplate(id string, variables any) error
+	SendCardViaTemplateContext(ctx context.Context, id string, variables any) error
 
 	SendMessage(msg Message) error
+
+	// SendMessageContext 发送消息
+	//
+	// ctx 被取消时，会立即中断限流等待以及正在进行中的请求，并返回 ctx.Err()
+	SendMessageContext(ctx context.Context, msg Message) error
+
+	// SendMessageWithResult 发送消息，并返回飞书的响应、X-Tt-Logid、请求次数、耗时等信息
+	//
+	// 发送失败时 SendResult 同样不为空。MultiBot 同时向多个 webhook 发送，不记录结果，返回的 SendResult 为零值
+	SendMessageWithResult(ctx context.Context, msg Message) (*SendResult, error)
 }
 
 type Message interface {
@@ -65,6 +93,23 @@ type MessageBody struct {
 	Card    *json.RawMessage    `json:"card,omitempty"`
 }
 
+var _ Message = (*MessageBody)(nil)
+
+// Apply 复制当前消息体，已构建好的 MessageBody 可以直接作为 Message 发送
+func (mb MessageBody) Apply(body *MessageBody) error {
+	body.MsgType = mb.MsgType
+
+	body.Content = nil
+	if mb.Content != nil {
+		content := *mb.Content
+		content.Post = cloneRawMessage(content.Post)
+		body.Content = &content
+	}
+
+	body.Card = cloneRawMessage(mb.Card)
+	return nil
+}
+
 type MessageBodyContent struct {
 	Text        string           `json:"text,omitempty"`
 	Post        *json.RawMessage `json:"post,omitempty"`
@@ -75,7 +120,7 @@ type MessageBodyContent struct {
 type MessageBodyCard struct {
 	Header       *json.RawMessage `json:"header,omitempty"`
 	Elements     *json.RawMessage `json:"elements,omitempty"`
-	I18nElements *json.RawMessage `json:"i18n_elements"`
+	I18nElements *json.RawMessage `json:"i18n_elements,omitempty"`
 	Config       *json.RawMessage `json:"config,omitempty"`
 	CardLink     *json.RawMessage `json:"card_link,omitempty"`
 }
@@ -96,37 +141,135 @@ func NewBot(webhook string, opts *BotOptions) Bot {
 	}
 	opts.init()
 
-	b := &bot{opts: opts}
+	b := &bot{opts: opts, clock: clock.WithOffset(opts.Clock, opts.ClockSkew)}
+	b.sendMethods = sendMethods{sendMessage: chainMiddlewares(opts.Middlewares, SenderFunc(b.sendMessage)).SendMessage}
 
-	if s := strings.TrimSpace(webhook); strings.Contains(s, "/open-apis/bot") {
+	b.baseURL, b.apiVersion = opts.BaseURL, "v2"
+	s := strings.TrimSpace(webhook)
+	if wh, err := ParseWebhook(s); err == nil {
+		b.webhookAccessToken, b.apiVersion = wh.Token, wh.Version
+		if b.baseURL == "" {
+			b.baseURL = wh.BaseURL
+		}
+	} else if u, err := url.Parse(s); err == nil && u.Host != "" && (u.Scheme == "https" || u.Scheme == "http") {
+		// 非标准的完整地址（例如 token 不是 UUID），保留其中的域名，不能回退到飞书的域名
+		b.webhookAccessToken = path.Base(u.Path)
+		if m := _webhookPathRegexp.FindStringSubmatch(u.Path); m != nil {
+			b.apiVersion = m[1]
+		}
+		if b.baseURL == "" {
+			b.baseURL = u.Scheme + "://" + u.Host
+		}
+	} else if strings.Contains(s, "/open-apis/bot") {
 		b.webhookAccessToken = path.Base(s)
 	} else {
 		b.webhookAccessToken = s
 	}
+	if b.baseURL == "" {
+		b.baseURL = feishuBaseURL
+	}
 
 	if opts.limiterEnabled() {
-		b.limiterSecond = rate.NewLimiter(rate.Limit(opts.LimiterPerSecond), opts.LimiterPerSecond)
-		b.limiterMinute = rate.NewLimiter(rate.Every(time.Minute/time.Duration(opts.LimiterPerMinute)), opts.LimiterPerMinute)
+		b.limiter = opts.Limiter
+		if b.limiter == nil {
+			b.limiter = opts.LimiterRegistry.limiter(b.webhookAccessToken, opts.LimiterPerSecond, opts.LimiterPerMinute, b.clock)
+		}
 	}
 
-	b.cli = xhttpclient.NewClient().BaseURL(opts.BaseURL)
+	b.cli = opts.httpClient()
+
+	b.observer = opts.Observer
+	if opts.Logger != nil {
+		b.observer = multiObserver{opts.Observer, &slogObserver{logger: opts.Logger, waitThreshold: opts.LimiterWaitLogThreshold}}
+	}
 
 	return b
 }
 
 type BotOptions struct {
+	// BaseURL 开放平台地址，为空时使用 webhook 中的域名；webhook 仅为 access token 时默认为 https://open.feishu.cn
 	BaseURL                            string
 	LimiterPerSecond, LimiterPerMinute int
 	SecretKey                          string
 
+	// Limiter 自定义限流器。设置后 LimiterPerSecond、LimiterPerMinute 不再生效
+	Limiter Limiter
+
+	// LimiterRegistry 未设置 Limiter 时，从中获取 webhook access token 以及限流配置对应的限流器，默认为 DefaultLimiterRegistry
+	LimiterRegistry *LimiterRegistry
+
+	// Retry 发送失败时的重试策略，为空时不重试
+	Retry *RetryPolicy
+
+	// HTTPClient 自定义 HTTP 客户端。设置后 Transport、ConnectTimeout、TLSConfig、UseEnvironmentProxy 不再生效
+	HTTPClient *http.Client
+
+	// Transport 自定义 http.RoundTripper，例如用于埋点、连接池配置。设置后 ConnectTimeout、TLSConfig、UseEnvironmentProxy 不再生效
+	Transport http.RoundTripper
+
+	// RequestTimeout 单次请求（不包含限流等待、重试间隔）的超时时间，默认为 30 秒，小于 0 时不限制
+	RequestTimeout time.Duration
+
+	// ConnectTimeout 建立连接的超时时间，默认为 10 秒，小于 0 时不限制
+	ConnectTimeout time.Duration
+
+	// TLSConfig 自定义 TLS 配置，例如信任私有 CA
+	TLSConfig *tls.Config
+
+	// UseEnvironmentProxy 是否使用环境变量 HTTPS_PROXY、HTTP_PROXY、NO_PROXY 中的代理配置，默认不使用
+	UseEnvironmentProxy bool
+
+	// Clock 时间来源，用于签名时间戳、限流以及重试间隔，默认为系统时钟
+	Clock clock.Clock
+
+	// ClockSkew 本机时间与飞书服务器时间的偏差，生成签名时间戳、限流时在本机时间上加上该值
+	//
+	// 飞书会校验签名时间戳，本机时间偏差过大时签名校验失败（19021）
+	ClockSkew time.Duration
+
+	// Observer 观察发送流程中的事件，默认为 NopObserver，参考 PrometheusObserver
+	Observer Observer
+
+	// Logger 输出发送、重试、错误等日志，为空时不输出
+	Logger *slog.Logger
+
+	// LimiterWaitLogThreshold 限流等待超过该时长时输出 Warn 日志，默认为 1 秒
+	LimiterWaitLogThreshold time.Duration
+
+	// Middlewares 发送流程的中间件，按顺序由外到内执行，参考 Middleware
+	Middlewares []Middleware
+
+	// HookAfterMessageApply 在所有 AfterApply 中间件之后执行，多个处理逻辑建议使用 AfterApply
 	HookAfterMessageApply func(body *MessageBody) error
 }
 
 func NewBotOptions() *BotOptions { return &BotOptions{} }
 
 func (opts *BotOptions) init() {
-	if strings.TrimSpace(opts.BaseURL) == "" {
-		opts.BaseURL = "https://open.feishu.cn"
+	opts.BaseURL = strings.TrimSpace(opts.BaseURL)
+
+	if opts.LimiterRegistry == nil {
+		opts.LimiterRegistry = DefaultLimiterRegistry
+	}
+
+	if opts.Clock == nil {
+		opts.Clock = clock.System()
+	}
+
+	if opts.Observer == nil {
+		opts.Observer = NopObserver{}
+	}
+
+	if opts.LimiterWaitLogThreshold == 0 {
+		opts.LimiterWaitLogThreshold = time.Second
+	}
+
+	if opts.RequestTimeout == 0 {
+		opts.RequestTimeout = 30 * time.Second
+	}
+
+	if opts.ConnectTimeout == 0 {
+		opts.ConnectTimeout = 10 * time.Second
 	}
 
 	if opts.limiterEnabled() {
@@ -143,6 +286,10 @@ func (opts *BotOptions) init() {
 }
 
 func (opts *BotOptions) limiterEnabled() bool {
+	if opts.Limiter != nil {
+		return true
+	}
+
 	if opts.LimiterPerSecond <= -1 || opts.LimiterPerMinute <= -1 {
 		return false
 	}
@@ -165,11 +312,87 @@ func (opts *BotOptions) SetLimiterPerMinute(n int) *BotOptions {
 	return opts
 }
 
+func (opts *BotOptions) SetLimiter(l Limiter) *BotOptions {
+	opts.Limiter = l
+	return opts
+}
+
+func (opts *BotOptions) SetLimiterRegistry(r *LimiterRegistry) *BotOptions {
+	opts.LimiterRegistry = r
+	return opts
+}
+
 func (opts *BotOptions) SetSecretKey(s string) *BotOptions {
 	opts.SecretKey = s
 	return opts
 }
 
+func (opts *BotOptions) SetRetry(policy *RetryPolicy) *BotOptions {
+	opts.Retry = policy
+	return opts
+}
+
+func (opts *BotOptions) SetHTTPClient(c *http.Client) *BotOptions {
+	opts.HTTPClient = c
+	return opts
+}
+
+func (opts *BotOptions) SetTransport(rt http.RoundTripper) *BotOptions {
+	opts.Transport = rt
+	return opts
+}
+
+func (opts *BotOptions) SetRequestTimeout(d time.Duration) *BotOptions {
+	opts.RequestTimeout = d
+	return opts
+}
+
+func (opts *BotOptions) SetConnectTimeout(d time.Duration) *BotOptions {
+	opts.ConnectTimeout = d
+	return opts
+}
+
+func (opts *BotOptions) SetTLSConfig(cfg *tls.Config) *BotOptions {
+	opts.TLSConfig = cfg
+	return opts
+}
+
+func (opts *BotOptions) SetUseEnvironmentProxy(b bool) *BotOptions {
+	opts.UseEnvironmentProxy = b
+	return opts
+}
+
+func (opts *BotOptions) SetClock(c clock.Clock) *BotOptions {
+	opts.Clock = c
+	return opts
+}
+
+func (opts *BotOptions) SetClockSkew(d time.Duration) *BotOptions {
+	opts.ClockSkew = d
+	return opts
+}
+
+func (opts *BotOptions) SetObserver(o Observer) *BotOptions {
+	opts.Observer = o
+	return opts
+}
+
+func (opts *BotOptions) SetLogger(l *slog.Logger) *BotOptions {
+	opts.Logger = l
+	return opts
+}
+
+func (opts *BotOptions) SetLimiterWaitLogThreshold(d time.Duration) *BotOptions {
+	opts.LimiterWaitLogThreshold = d
+	return opts
+}
+
+// Use 追加中间件
+func (opts *BotOptions) Use(middlewares ...Middleware) *BotOptions {
+	opts.Middlewares = append(opts.Middlewares, middlewares...)
+	return opts
+}
+
 func (opts *BotOptions) SetHookAfterMessageApply(f func(body *MessageBody) error) *BotOptions {
 	opts.HookAfterMessageApply = f
 	return opts
@@ -0,0 +1,243 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+var (
+	// ErrQueueFull 队列已满（OverflowError）
+	ErrQueueFull = errors.New("async bot: queue full")
+
+	// ErrMessageDropped 消息因队列已满被丢弃（OverflowDropOldest、OverflowDropNewest），通过 AsyncBotOptions.OnResult 回调
+	ErrMessageDropped = errors.New("async bot: message dropped")
+
+	// ErrAsyncBotClosed AsyncBot 已关闭
+	ErrAsyncBotClosed = errors.New("async bot: closed")
+)
+
+// OverflowPolicy 队列已满时的处理方式
+type OverflowPolicy int
+
+const (
+	// OverflowBlock 阻塞直到队列有空位
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest 丢弃队列中最早的消息
+	OverflowDropOldest
+	// OverflowDropNewest 丢弃当前入队的消息
+	OverflowDropNewest
+	// OverflowError 返回 ErrQueueFull
+	OverflowError
+)
+
+type AsyncBotOptions struct {
+	// QueueSize 队列长度，默认 1024
+	QueueSize int
+
+	// Workers 后台发送的协程数量，默认 1
+	//
+	// 多个协程共享 Bot 的限流器，大于 1 时不保证消息的发送顺序
+	Workers int
+
+	OverflowPolicy OverflowPolicy
+
+	// OnResult 每条消息的发送结果（包括被丢弃的消息）
+	//
+	// 发送结果在后台协程中回调，此时不能调用 Close；被丢弃的消息在 Enqueue 的调用方协程中回调
+	OnResult func(msg Message, err error)
+}
+
+func NewAsyncBotOptions() *AsyncBotOptions { return &AsyncBotOptions{} }
+
+func (opts *AsyncBotOptions) init() {
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1024
+	}
+	if opts.Workers <= 0 {
+		opts.Workers = 1
+	}
+}
+
+func (opts *AsyncBotOptions) SetQueueSize(n int) *AsyncBotOptions {
+	opts.QueueSize = n
+	return opts
+}
+
+func (opts *AsyncBotOptions) SetWorkers(n int) *AsyncBotOptions {
+	opts.Workers = n
+	return opts
+}
+
+func (opts *AsyncBotOptions) SetOverflowPolicy(policy OverflowPolicy) *AsyncBotOptions {
+	opts.OverflowPolicy = policy
+	return opts
+}
+
+func (opts *AsyncBotOptions) SetOnResult(f func(msg Message, err error)) *AsyncBotOptions {
+	opts.OnResult = f
+	return opts
+}
+
+// AsyncBot 异步发送消息
+//
+// 消息先进入内存队列，再由后台协程通过 Bot 发送，入队时不会因为限流而阻塞
+type AsyncBot struct {
+	bot  Bot
+	opts *AsyncBotOptions
+
+	mu      sync.RWMutex
+	closed  bool
+	closing chan struct{}
+	once    sync.Once
+	queue   chan Message
+
+	ctx    context.Context
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+}
+
+func NewAsyncBot(b Bot, opts *AsyncBotOptions) *AsyncBot {
+	if opts == nil {
+		opts = &AsyncBotOptions{}
+	}
+	opts.init()
+
+	ab := &AsyncBot{
+		bot:     b,
+		opts:    opts,
+		closing: make(chan struct{}),
+		queue:   make(chan Message, opts.QueueSize),
+	}
+	ab.ctx, ab.cancel = context.WithCancelCause(context.Background())
+
+	ab.wg.Add(opts.Workers)
+	for i := 0; i < opts.Workers; i++ {
+		go ab.work()
+	}
+
+	return ab
+}
+
+// Enqueue 消息入队
+//
+// 队列已满时按 AsyncBotOptions.OverflowPolicy 处理
+func (ab *AsyncBot) Enqueue(msg Message) error {
+	return ab.EnqueueContext(context.Background(), msg)
+}
+
+// EnqueueContext 消息入队
+//
+// 仅 OverflowBlock 会使用 ctx：ctx 被取消时停止等待并返回 ctx.Err()
+func (ab *AsyncBot) EnqueueContext(ctx context.Context, msg Message) error {
+	dropped, err := ab.enqueue(ctx, msg)
+	for _, m := range dropped {
+		ab.report(m, ErrMessageDropped)
+	}
+	return err
+}
+
+// enqueue 在持有读锁时入队，返回被丢弃的消息，由调用方在释放锁之后回调 OnResult
+func (ab *AsyncBot) enqueue(ctx context.Context, msg Message) (dropped []Message, err error) {
+	ab.mu.RLock()
+	defer ab.mu.RUnlock()
+
+	if ab.closed {
+		return nil, ErrAsyncBotClosed
+	}
+
+	select {
+	case ab.queue <- msg:
+		return nil, nil
+	default:
+	}
+
+	switch ab.opts.OverflowPolicy {
+	case OverflowDropOldest:
+		for {
+			select {
+			case ab.queue <- msg:
+				return dropped, nil
+			default:
+			}
+
+			select {
+			case old := <-ab.queue:
+				dropped = append(dropped, old)
+			default:
+			}
+		}
+	case OverflowDropNewest:
+		return []Message{msg}, nil
+	case OverflowError:
+		return nil, ErrQueueFull
+	default:
+		select {
+		case ab.queue <- msg:
+			return nil, nil
+		case <-ab.closing:
+			return nil, ErrAsyncBotClosed
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// Len 队列中等待发送的消息数量
+func (ab *AsyncBot) Len() int {
+	return len(ab.queue)
+}
+
+// Close 停止接收新消息，并等待队列中的消息发送完成
+//
+// ctx 被取消时，会中断正在发送的消息，剩余的消息以 ctx 的错误回调 OnResult，并返回 ctx.Err()
+func (ab *AsyncBot) Close(ctx context.Context) error {
+	ab.once.Do(func() {
+		close(ab.closing)
+
+		ab.mu.Lock()
+		ab.closed = true
+		close(ab.queue)
+		ab.mu.Unlock()
+	})
+
+	done := make(chan struct{})
+	go func() {
+		ab.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		ab.cancel(nil)
+		return nil
+	case <-ctx.Done():
+		ab.cancel(ctx.Err())
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (ab *AsyncBot) work() {
+	defer ab.wg.Done()
+
+	for msg := range ab.queue {
+		if ab.ctx.Err() != nil {
+			ab.report(msg, context.Cause(ab.ctx))
+			continue
+		}
+
+		err := ab.bot.SendMessageContext(ab.ctx, msg)
+		if err != nil && ab.ctx.Err() != nil && errors.Is(err, context.Canceled) {
+			// 被 Close 中断，以调用方 ctx 的错误回调
+			err = context.Cause(ab.ctx)
+		}
+		ab.report(msg, err)
+	}
+}
+
+func (ab *AsyncBot) report(msg Message, err error) {
+	if f := ab.opts.OnResult; f != nil {
+		f(msg, err)
+	}
+}
@@ -0,0 +1,182 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newBlockingServer 每收到一个请求就向 received 发送消息内容，直到 release 被关闭后才响应
+func newBlockingServer(received chan<- string, release <-chan struct{}) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req APIRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		received <- req.Content.Text
+		select {
+		case <-release:
+		case <-r.Context().Done():
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+}
+
+type asyncResults struct {
+	mu      sync.Mutex
+	results map[string]error
+}
+
+func (rs *asyncResults) onResult(msg Message, err error) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	if rs.results == nil {
+		rs.results = make(map[string]error)
+	}
+	rs.results[string(msg.(textMessage))] = err
+}
+
+func (rs *asyncResults) get(text string) (error, bool) {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+	err, ok := rs.results[text]
+	return err, ok
+}
+
+func TestAsyncBot(t *testing.T) {
+	newAsyncBot := func(t *testing.T, policy OverflowPolicy, rs *asyncResults) (*AsyncBot, chan string, chan struct{}) {
+		received := make(chan string, 16)
+		release := make(chan struct{})
+		srv := newBlockingServer(received, release)
+		t.Cleanup(srv.Close)
+
+		b := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()))
+		ab := NewAsyncBot(b, NewAsyncBotOptions().SetQueueSize(1).SetOverflowPolicy(policy).SetOnResult(rs.onResult))
+
+		// 第一条消息被后台协程取出后阻塞在请求中，第二条消息占满队列
+		requireNoError(t, ab.Enqueue(textMessage("1")))
+		<-received
+		requireNoError(t, ab.Enqueue(textMessage("2")))
+		return ab, received, release
+	}
+
+	t.Run("drop_newest", func(t *testing.T) {
+		var rs asyncResults
+		ab, _, release := newAsyncBot(t, OverflowDropNewest, &rs)
+
+		requireNoError(t, ab.Enqueue(textMessage("3")))
+		if err, _ := rs.get("3"); !errors.Is(err, ErrMessageDropped) {
+			t.Errorf("Actual result: %v, want: %v", err, ErrMessageDropped)
+		}
+
+		close(release)
+		requireNoError(t, ab.Close(context.Background()))
+		for _, text := range []string{"1", "2"} {
+			if err, ok := rs.get(text); !ok || err != nil {
+				t.Errorf("Actual result(%s): %v, %v", text, err, ok)
+			}
+		}
+	})
+
+	t.Run("drop_oldest", func(t *testing.T) {
+		var rs asyncResults
+		ab, _, release := newAsyncBot(t, OverflowDropOldest, &rs)
+
+		requireNoError(t, ab.Enqueue(textMessage("3")))
+		if err, _ := rs.get("2"); !errors.Is(err, ErrMessageDropped) {
+			t.Errorf("Actual result: %v, want: %v", err, ErrMessageDropped)
+		}
+
+		close(release)
+		requireNoError(t, ab.Close(context.Background()))
+		if err, ok := rs.get("3"); !ok || err != nil {
+			t.Errorf("Actual result: %v, %v", err, ok)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		var rs asyncResults
+		ab, _, release := newAsyncBot(t, OverflowError, &rs)
+
+		if err := ab.Enqueue(textMessage("3")); !errors.Is(err, ErrQueueFull) {
+			t.Errorf("Actual error: %v, want: %v", err, ErrQueueFull)
+		}
+
+		close(release)
+		requireNoError(t, ab.Close(context.Background()))
+		if err := ab.Enqueue(textMessage("4")); !errors.Is(err, ErrAsyncBotClosed) {
+			t.Errorf("Actual error: %v, want: %v", err, ErrAsyncBotClosed)
+		}
+	})
+
+	t.Run("block", func(t *testing.T) {
+		var rs asyncResults
+		ab, _, release := newAsyncBot(t, OverflowBlock, &rs)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := ab.EnqueueContext(ctx, textMessage("3")); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Actual error: %v, want: %v", err, context.DeadlineExceeded)
+		}
+
+		close(release)
+		requireNoError(t, ab.Close(context.Background()))
+	})
+
+	t.Run("close_in_on_result", func(t *testing.T) {
+		received := make(chan string, 16)
+		release := make(chan struct{})
+		srv := newBlockingServer(received, release)
+		t.Cleanup(srv.Close)
+
+		var (
+			ab       *AsyncBot
+			closeErr error
+		)
+		b := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()))
+		ab = NewAsyncBot(b, NewAsyncBotOptions().SetQueueSize(1).SetOverflowPolicy(OverflowDropNewest).SetOnResult(func(msg Message, err error) {
+			// 被丢弃的消息在 Enqueue 的调用方协程中回调，可以调用 Close
+			if errors.Is(err, ErrMessageDropped) {
+				close(release)
+				closeErr = ab.Close(context.Background())
+			}
+		}))
+
+		requireNoError(t, ab.Enqueue(textMessage("1")))
+		<-received
+		requireNoError(t, ab.Enqueue(textMessage("2")))
+
+		done := make(chan error, 1)
+		go func() { done <- ab.Enqueue(textMessage("3")) }()
+		select {
+		case err := <-done:
+			requireNoError(t, err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("Enqueue deadlocked with Close in OnResult")
+		}
+		requireNoError(t, closeErr)
+		if err := ab.Enqueue(textMessage("4")); !errors.Is(err, ErrAsyncBotClosed) {
+			t.Errorf("Actual error: %v, want: %v", err, ErrAsyncBotClosed)
+		}
+	})
+
+	t.Run("close_timeout", func(t *testing.T) {
+		var rs asyncResults
+		ab, _, _ := newAsyncBot(t, OverflowBlock, &rs)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := ab.Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Actual error: %v, want: %v", err, context.DeadlineExceeded)
+		}
+		for _, text := range []string{"1", "2"} {
+			if err, _ := rs.get(text); !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("Actual result(%s): %v, want: %v", text, err, context.DeadlineExceeded)
+			}
+		}
+	})
+}
@@ -1,150 +1,226 @@
 package feishu_bot_api
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"time"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
 
-	"github.com/electricbubble/xhttpclient"
-	"golang.org/x/time/rate"
+	"github.com/electricbubble/feishu-bot-api/v2/clock"
 )
 
 type bot struct {
-	webhookAccessToken           string
-	opts                         *BotOptions
-	limiterSecond, limiterMinute *rate.Limiter
-	cli                          *xhttpclient.XClient
+	sendMethods
+
+	webhookAccessToken string
+	baseURL            string
+	apiVersion         string
+	opts               *BotOptions
+	limiter            Limiter
+	cli                *http.Client
+	clock              clock.Clock
+	observer           Observer
 }
 
-type apiRequest struct {
+// APIRequest webhook 请求内容，启用签名校验时包含 timestamp 与 sign
+type APIRequest struct {
 	MessageBody
 	Timestamp int64  `json:"timestamp,omitempty"`
 	Sign      string `json:"sign,omitempty"`
 }
 
-type apiResponse struct {
+// APIResponse webhook 响应内容
+type APIResponse struct {
 	Code int    `json:"code"`
 	Msg  string `json:"msg"`
 	Data any    `json:"data"`
 }
 
-func (b *bot) SendText(content string) error {
-	return b.SendMessage(textMessage(content))
-}
+func (b *bot) sendMessage(ctx context.Context, msg Message) error {
+	var body MessageBody
+	if err := msg.Apply(&body); err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
 
-func (b *bot) SendRichText(rt *RichTextBuilder, multiLanguage ...*RichTextBuilder) error {
-	return b.SendMessage(richTextMessage(append([]*RichTextBuilder{rt}, multiLanguage...)))
-}
+	if f := b.opts.HookAfterMessageApply; f != nil {
+		if err := f(&body); err != nil {
+			return fmt.Errorf("hook(AfterMessageApply): %w", err)
+		}
+	}
 
-func (b *bot) SendGroupBusinessCard(chatID string) error {
-	return b.SendMessage(groupBusinessCard(chatID))
-}
+	var (
+		retry   = b.opts.Retry
+		webhook = maskToken(b.webhookAccessToken)
+		attempt int
+		start   = b.clock.Now()
+	)
 
-func (b *bot) SendImage(imgKey string) error {
-	return b.SendMessage(imageMessage(imgKey))
+	err := func() error {
+		for attempt = 1; ; attempt++ {
+			err := b.send(ctx, body, attempt)
+			if err == nil || !retry.shouldRetry(attempt, err) {
+				return err
+			}
+
+			backoff := retry.backoff(attempt)
+			b.observer.Retry(ctx, RetryEvent{Webhook: webhook, MsgType: body.MsgType, Attempt: attempt, Backoff: backoff, Err: err})
+			if err := sleepContext(ctx, b.clock, backoff); err != nil {
+				return err
+			}
+		}
+	}()
+	if result := sendResultFromContext(ctx); result != nil {
+		result.Latency += b.clock.Now().Sub(start)
+	}
+	if err != nil {
+		b.observer.Drop(ctx, DropEvent{Webhook: webhook, MsgType: body.MsgType, Attempts: attempt, Err: err})
+	}
+	return err
 }
 
-func (b *bot) SendCard(globalConf *CardGlobalConfig, card *CardBuilder, multiLanguage ...*CardBuilder) error {
-	return b.SendMessage(cardMessage{
-		globalConf: globalConf,
-		builders:   append([]*CardBuilder{card}, multiLanguage...),
-	})
-}
-func (b *bot) SendCardViaTemplate(id string, variables any) error {
-	return b.SendMessage(cardMessageViaTemplate{id: id, variables: variables})
-}
+// send 发送一次请求。每次调用都会重新生成 timestamp 与 sign
+func (b *bot) send(ctx context.Context, body MessageBody, attempt int) (err error) {
+	obs, webhook := b.observer, maskToken(b.webhookAccessToken)
 
-func (b *bot) SendMessage(msg Message) (err error) {
-	if b.opts.limiterEnabled() {
-		if err := b.wait(); err != nil {
+	result := sendResultFromContext(ctx)
+	if result == nil {
+		result = &SendResult{}
+	}
+	result.Attempts++
+
+	if b.limiter != nil {
+		obs.LimiterWaitStart(ctx, LimiterWaitEvent{Webhook: webhook})
+		start := b.clock.Now()
+		err := b.limiter.Wait(ctx)
+		wait := b.clock.Now().Sub(start)
+		result.LimiterWait += wait
+		obs.LimiterWaitEnd(ctx, LimiterWaitEvent{Webhook: webhook, Duration: wait, Err: err})
+		if err != nil {
 			return err
 		}
 	}
 
-	var req apiRequest
+	req := APIRequest{MessageBody: body}
 	if s := b.opts.SecretKey; s != "" {
-		req.Timestamp = time.Now().Unix()
+		req.Timestamp = b.clock.Now().Unix()
 		if req.Sign, err = genSignature(req.Timestamp, s); err != nil {
 			return fmt.Errorf("gen signature: %w", err)
 		}
 	}
 
-	if err := msg.Apply(&req.MessageBody); err != nil {
-		return fmt.Errorf("apply: %w", err)
+	hooks := sendHooksFromContext(ctx)
+	for _, f := range hooks.beforeHTTP {
+		if err := f(ctx, &req); err != nil {
+			return fmt.Errorf("hook(BeforeHTTP): %w", err)
+		}
 	}
 
-	if f := b.opts.HookAfterMessageApply; f != nil {
-		if err := f(&req.MessageBody); err != nil {
-			return fmt.Errorf("hook(AfterMessageApply): %w", err)
-		}
+	reqCtx := ctx
+	if d := b.opts.RequestTimeout; d > 0 {
+		var cancel context.CancelFunc
+		reqCtx, cancel = context.WithTimeout(ctx, d)
+		defer cancel()
 	}
 
-	var resp apiResponse
-	_, respBody, err := b.cli.Do(&resp, nil,
-		xhttpclient.
-			NewPost().
-			Path("/open-apis/bot/v2/hook", b.webhookAccessToken).
-			Body(req),
-	)
+	reqBody, err := json.Marshal(req)
 	if err != nil {
-		return fmt.Errorf("unexpected: %w (resp body: %s)", err, respBody)
+		return fmt.Errorf("marshal: %w", err)
 	}
-
-	if resp.Code != 0 {
-		return fmt.Errorf("api error: %s", respBody)
+	result.PayloadBytes = len(reqBody)
+	obs.RequestSent(ctx, RequestEvent{Webhook: webhook, MsgType: body.MsgType, PayloadBytes: len(reqBody), Attempt: attempt})
+
+	var resp APIResponse
+	start := b.clock.Now()
+	statusCode, logID, respBody, err := b.do(reqCtx, reqBody, &resp)
+	info := ResponseInfo{Request: &req, StatusCode: statusCode, LogID: logID, Latency: b.clock.Now().Sub(start)}
+	result.Code, result.Msg, result.Data, result.StatusCode, result.LogID = resp.Code, resp.Msg, resp.Data, statusCode, logID
+
+	switch {
+	case err != nil:
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			err = ctxErr
+		} else {
+			err = fmt.Errorf("unexpected: %w (resp body: %s)", err, respBody)
+		}
+	case resp.Code != 0 || !isSuccessStatus(statusCode):
+		info.Response = &resp
+		err = newAPIError(statusCode, resp, respBody)
+	default:
+		info.Response = &resp
 	}
 
-	return
-}
-
-func (b *bot) wait() error {
+	info.Err = err
+	hooks.runAfterResponse(ctx, info)
+	obs.ResponseReceived(ctx, ResponseEvent{
+		Webhook:    webhook,
+		MsgType:    body.MsgType,
+		Attempt:    attempt,
+		Latency:    info.Latency,
+		Code:       resp.Code,
+		Msg:        resp.Msg,
+		StatusCode: statusCode,
+		LogID:      logID,
+		Err:        err,
+	})
 
-REDO:
+	return err
+}
 
-	now := time.Now()
+// do 发送 webhook 请求，并将响应内容解析到 resp
+//
+// 返回 HTTP 状态码、响应头 X-Tt-Logid 以及响应内容。HTTP 状态码异常且响应内容无法解析时不返回 error，由调用方根据状态码处理
+func (b *bot) do(ctx context.Context, reqBody []byte, resp *APIResponse) (int, string, []byte, error) {
+	endpoint, err := url.JoinPath(b.baseURL, "/open-apis/bot", b.apiVersion, "hook", b.webhookAccessToken)
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("join path: %w", err)
+	}
 
-	{
-		ts := time.Unix(now.Unix(), 0)
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(reqBody))
+	if err != nil {
+		return 0, "", nil, fmt.Errorf("new request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json; charset=utf-8")
 
-		if b.limiterSecond.TokensAt(ts) <= 0 {
-			time.Sleep(ts.Add(time.Second).Sub(now))
-			goto REDO
+	httpResp, err := b.cli.Do(httpReq)
+	if err != nil {
+		// *url.Error 中包含完整的 webhook 地址，避免 access token 泄露到日志中
+		var ue *url.Error
+		if errors.As(err, &ue) && ue.URL == endpoint && b.webhookAccessToken != "" {
+			if masked, err := url.JoinPath(b.baseURL, "/open-apis/bot", b.apiVersion, "hook", maskToken(b.webhookAccessToken)); err == nil {
+				ue.URL = masked
+			}
 		}
+		return 0, "", nil, err
+	}
+	defer func() { _ = httpResp.Body.Close() }()
 
-		rs := b.limiterSecond.ReserveN(ts, 1)
-		if !rs.OK() {
-			return errors.New("limiter(second): not allowed to act")
-		}
+	logID := httpResp.Header.Get("X-Tt-Logid")
 
-		switch d := rs.DelayFrom(ts); d {
-		case rate.InfDuration:
-			return errors.New("limiter(second): cannot grant the token")
-		case 0:
-		default:
-			time.Sleep(d)
-		}
+	respBody, err := io.ReadAll(httpResp.Body)
+	if err != nil {
+		return httpResp.StatusCode, logID, respBody, fmt.Errorf("read body: %w", err)
 	}
 
-	{
-		tm := time.Unix(now.Unix()-int64(now.Second()), 0)
+	if err := json.Unmarshal(respBody, resp); err != nil && isSuccessStatus(httpResp.StatusCode) {
+		return httpResp.StatusCode, logID, respBody, fmt.Errorf("unmarshal: %w", err)
+	}
 
-		if b.limiterMinute.TokensAt(tm) <= 0 {
-			time.Sleep(tm.Add(time.Minute).Sub(now))
-			goto REDO
-		}
+	return httpResp.StatusCode, logID, respBody, nil
+}
 
-		rm := b.limiterMinute.ReserveN(tm, 1)
-		if !rm.OK() {
-			return errors.New("limiter(minute): not allowed to act")
-		}
-		switch d := rm.DelayFrom(tm); d {
-		case rate.InfDuration:
-			return errors.New("limiter(minute): cannot grant the token")
-		case 0:
-		default:
-			time.Sleep(d)
-		}
-	}
+func isSuccessStatus(code int) bool {
+	return code >= 200 && code < 300
+}
 
-	return nil
+// tokens 限流器当前剩余的令牌数，未启用限流时返回 +Inf
+func (b *bot) tokens() float64 {
+	if b.limiter == nil {
+		return math.Inf(1)
+	}
+	return b.limiter.Tokens()
 }
@@ -0,0 +1,81 @@
+package feishu_bot_api
+
+import "context"
+
+// sendMethods 基于 sendMessage 实现 Bot 中的各类发送方法
+type sendMethods struct {
+	sendMessage func(ctx context.Context, msg Message) error
+}
+
+func (s sendMethods) SendText(content string) error {
+	return s.SendTextContext(context.Background(), content)
+}
+
+func (s sendMethods) SendTextContext(ctx context.Context, content string) error {
+	return s.SendMessageContext(ctx, textMessage(content))
+}
+
+func (s sendMethods) SendRichText(rt *RichTextBuilder, multiLanguage ...*RichTextBuilder) error {
+	return s.SendRichTextContext(context.Background(), rt, multiLanguage...)
+}
+
+func (s sendMethods) SendRichTextContext(ctx context.Context, rt *RichTextBuilder, multiLanguage ...*RichTextBuilder) error {
+	return s.SendMessageContext(ctx, richTextMessage(append([]*RichTextBuilder{rt}, multiLanguage...)))
+}
+
+func (s sendMethods) SendGroupBusinessCard(chatID string) error {
+	return s.SendGroupBusinessCardContext(context.Background(), chatID)
+}
+
+func (s sendMethods) SendGroupBusinessCardContext(ctx context.Context, chatID string) error {
+	return s.SendMessageContext(ctx, groupBusinessCard(chatID))
+}
+
+func (s sendMethods) SendImage(imgKey string) error {
+	return s.SendImageContext(context.Background(), imgKey)
+}
+
+func (s sendMethods) SendImageContext(ctx context.Context, imgKey string) error {
+	return s.SendMessageContext(ctx, imageMessage(imgKey))
+}
+
+func (s sendMethods) SendCard(globalConf *CardGlobalConfig, card *CardBuilder, multiLanguage ...*CardBuilder) error {
+	return s.SendCardContext(context.Background(), globalConf, card, multiLanguage...)
+}
+
+func (s sendMethods) SendCardContext(ctx context.Context, globalConf *CardGlobalConfig, card *CardBuilder, multiLanguage ...*CardBuilder) error {
+	return s.SendMessageContext(ctx, cardMessage{
+		globalConf: globalConf,
+		builders:   append([]*CardBuilder{card}, multiLanguage...),
+	})
+}
+
+func (s sendMethods) SendCardV2(card *CardV2Builder) error {
+	return s.SendCardV2Context(context.Background(), card)
+}
+
+func (s sendMethods) SendCardV2Context(ctx context.Context, card *CardV2Builder) error {
+	return s.SendMessageContext(ctx, cardMessageV2{card: card})
+}
+
+func (s sendMethods) SendCardViaTemplate(id string, variables any) error {
+	return s.SendCardViaTemplateContext(context.Background(), id, variables)
+}
+
+func (s sendMethods) SendCardViaTemplateContext(ctx context.Context, id string, variables any) error {
+	return s.SendMessageContext(ctx, cardMessageViaTemplate{id: id, variables: variables})
+}
+
+func (s sendMethods) SendMessage(msg Message) error {
+	return s.SendMessageContext(context.Background(), msg)
+}
+
+func (s sendMethods) SendMessageContext(ctx context.Context, msg Message) error {
+	return s.sendMessage(ctx, msg)
+}
+
+func (s sendMethods) SendMessageWithResult(ctx context.Context, msg Message) (*SendResult, error) {
+	result := &SendResult{}
+	err := s.SendMessageContext(withSendResult(ctx, result), msg)
+	return result, err
+}
@@ -3,7 +3,12 @@
 import (
 	"bytes"
 	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"slices"
 	"sync"
@@ -11,6 +16,7 @@
 	"testing"
 	"time"
 
+	"github.com/electricbubble/feishu-bot-api/v2/clock/clocktest"
 	"github.com/electricbubble/feishu-bot-api/v2/md"
 )
 
@@ -100,40 +106,51 @@ func Test_bot_wait(t *testing.T) {
 		go func() {
 			defer wg.Done()
 
+			// 从整分钟开始，避免跨窗口
+			clk := clocktest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).AutoAdvance(tt.wg == nil)
+
+			tt.opts.LimiterRegistry = NewLimiterRegistry()
+			tt.opts.Clock = clk
 			b := NewBot("", tt.opts).(*bot)
 			name := fmt.Sprintf("count-%d_s-%d_m-%d", tt.count, b.opts.LimiterPerSecond, b.opts.LimiterPerMinute)
 
 			t.Run(name, func(t *testing.T) {
 
-				{
-					var (
-						now  = time.Now()
-						next time.Time
-					)
-					if tt.count <= b.opts.LimiterPerMinute {
-						next = time.Unix(now.Unix(), 0).Add(time.Second)
-					} else {
-						next = time.Unix(now.Unix()-int64(now.Second()), 0).Add(time.Minute)
-					}
-					time.Sleep(next.Sub(now))
-				}
-
 				var (
-					start    = time.Now()
+					start    = clk.Now()
 					ms, mm   sync.Map
+					muDebug  sync.Mutex
 					bufDebug bytes.Buffer
 				)
 
+				// 并发场景下无法自动前进时钟，由后台协程推进到最早的定时器
+				if tt.wg != nil {
+					done := make(chan struct{})
+					defer close(done)
+					go func() {
+						for {
+							select {
+							case <-done:
+								return
+							case <-time.After(100 * time.Microsecond):
+								if next, ok := clk.Next(); ok {
+									clk.Set(next)
+								}
+							}
+						}
+					}()
+				}
+
 				for i := 1; i <= tt.count; i++ {
 					if tt.wg != nil {
 						tt.wg.Add(1)
 						go func() {
 							defer tt.wg.Done()
 
-							if err := b.wait(); err != nil {
+							if err := b.limiter.Wait(context.Background()); err != nil {
 								t.Errorf("Received unexpected error:\n%+v", err)
 							}
-							now := time.Now()
+							now := clk.Now()
 							{
 								counter := new(atomic.Int32)
 								_v, _ := ms.LoadOrStore(now.Unix(), counter)
@@ -146,14 +163,16 @@ func Test_bot_wait(t *testing.T) {
 								counter = _v.(*atomic.Int32)
 								counter.Add(1)
 							}
+							muDebug.Lock()
 							bufDebug.WriteString(fmt.Sprintf("%s\t%d", now.Format("2006-01-02 15:04:05.000"), now.Unix()))
+							muDebug.Unlock()
 							// t.Log(now.Format("2006-01-02 15:04:05.000"), now.Unix())
 						}()
 					} else {
-						if err := b.wait(); err != nil {
+						if err := b.limiter.Wait(context.Background()); err != nil {
 							t.Errorf("Received unexpected error:\n%+v", err)
 						}
-						now := time.Now()
+						now := clk.Now()
 						{
 							counter := new(atomic.Int32)
 							_v, _ := ms.LoadOrStore(now.Unix(), counter)
@@ -175,7 +194,7 @@ func Test_bot_wait(t *testing.T) {
 					tt.wg.Wait()
 				}
 
-				d := time.Since(start)
+				d := clk.Now().Sub(start)
 				if d < tt.wantDuration-offsetDuration {
 					t.Errorf("Actual min duration: %s, want: %s\nDEBUG:\n%s", d, tt.wantDuration-offsetDuration, bufDebug.String())
 				}
@@ -936,10 +955,106 @@ func Test_bot_Hook(t *testing.T) {
 			return fmt.Errorf("surprise: %s", body.Content.Text)
 		}
 
-		b := NewBot("tmp", NewBotOptions().SetHookAfterMessageApply(fn))
+		b := NewBot("tmp", NewBotOptions().SetLimiterRegistry(NewLimiterRegistry()).SetHookAfterMessageApply(fn))
 		err := b.SendText("hi")
 		if err.Error() != "hook(AfterMessageApply): surprise: hi" {
 			t.FailNow()
 		}
 	})
 }
+
+func Test_bot_SendMessageContext(t *testing.T) {
+	t.Run("cancel_in_flight_request", func(t *testing.T) {
+		release := make(chan struct{})
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			<-release
+		}))
+		defer srv.Close()
+		defer close(release)
+
+		b := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(50*time.Millisecond, cancel)
+
+		start := time.Now()
+		err := b.SendTextContext(ctx, "hi")
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("Actual error: %v, want: %v", err, context.Canceled)
+		}
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("Actual duration: %s, want: < 1s", d)
+		}
+	})
+
+	t.Run("cancel_limiter_wait", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+		}))
+		defer srv.Close()
+
+		b := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterPerSecond(1).SetLimiterPerMinute(1).SetLimiterRegistry(NewLimiterRegistry()))
+		requireNoError(t, b.SendText("hi"))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		err := b.SendTextContext(ctx, "hi")
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Actual error: %v, want: %v", err, context.DeadlineExceeded)
+		}
+		if d := time.Since(start); d > time.Second {
+			t.Errorf("Actual duration: %s, want: < 1s", d)
+		}
+	})
+}
+
+func Test_bot_signature(t *testing.T) {
+	tests := []struct {
+		name          string
+		skew          time.Duration
+		wantTimestamp int64
+		wantSign      string
+	}{
+		{
+			name:          "no_skew",
+			wantTimestamp: 1704067200,
+			wantSign:      "UmCuTDk9fOqxIFU/rgoZePOi1kozO/bHnw8J+AiB/iw=",
+		},
+		{
+			name:          "skew",
+			skew:          -30 * time.Second,
+			wantTimestamp: 1704067170,
+			wantSign:      "rf0mIOpSd0rbn3CW1TAyGuxhLfTi1moDX9rC8/frTTg=",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var req APIRequest
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				_ = json.NewDecoder(r.Body).Decode(&req)
+				_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+			}))
+			defer srv.Close()
+
+			clk := clocktest.NewFakeClock(time.Unix(1704067200, 0))
+			b := NewBot("tmp", NewBotOptions().
+				SetBaseURL(srv.URL).
+				SetSecretKey("secret").
+				SetLimiterRegistry(NewLimiterRegistry()).
+				SetClock(clk).
+				SetClockSkew(tt.skew),
+			)
+			requireNoError(t, b.SendText("hi"))
+
+			if req.Timestamp != tt.wantTimestamp {
+				t.Errorf("Actual timestamp: %d, want: %d", req.Timestamp, tt.wantTimestamp)
+			}
+			if req.Sign != tt.wantSign {
+				t.Errorf("Actual sign: %s, want: %s", req.Sign, tt.wantSign)
+			}
+		})
+	}
+}
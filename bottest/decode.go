@@ -0,0 +1,51 @@
+package bottest
+
+import (
+	"encoding/json"
+	"fmt"
+
+	feishu "github.com/electricbubble/feishu-bot-api/v2"
+)
+
+// Post 富文本消息，key 为语言，例如 zh_cn
+type Post map[string]PostContent
+
+// PostContent 单个语言的富文本内容
+type PostContent struct {
+	Title   string             `json:"title"`
+	Content [][]map[string]any `json:"content"`
+}
+
+// DecodeText 文本消息的内容
+func DecodeText(body feishu.MessageBody) (string, error) {
+	if body.MsgType != "text" || body.Content == nil {
+		return "", fmt.Errorf("bottest: not a text message: %s", body.MsgType)
+	}
+	return body.Content.Text, nil
+}
+
+// DecodePost 富文本消息的内容
+func DecodePost(body feishu.MessageBody) (Post, error) {
+	if body.MsgType != "post" || body.Content == nil || body.Content.Post == nil {
+		return nil, fmt.Errorf("bottest: not a post message: %s", body.MsgType)
+	}
+
+	var post Post
+	if err := json.Unmarshal(*body.Content.Post, &post); err != nil {
+		return nil, fmt.Errorf("bottest: unmarshal post: %w", err)
+	}
+	return post, nil
+}
+
+// DecodeCard 卡片消息的内容
+func DecodeCard(body feishu.MessageBody) (map[string]any, error) {
+	if body.MsgType != "interactive" || body.Card == nil {
+		return nil, fmt.Errorf("bottest: not a card message: %s", body.MsgType)
+	}
+
+	var card map[string]any
+	if err := json.Unmarshal(*body.Card, &card); err != nil {
+		return nil, fmt.Errorf("bottest: unmarshal card: %w", err)
+	}
+	return card, nil
+}
@@ -0,0 +1,207 @@
+// Package bottest 测试用的 Bot
+package bottest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"sync"
+
+	feishu "github.com/electricbubble/feishu-bot-api/v2"
+)
+
+var _ feishu.Bot = (*Recorder)(nil)
+
+// Call 一次发送
+type Call struct {
+	// Body 经过 Message.Apply、中间件、HookAfterMessageApply 处理后的消息
+	Body feishu.MessageBody
+	// Request 实际会发送的请求，启用签名校验时包含 timestamp 与 sign
+	Request feishu.APIRequest
+	// Err 本次发送最终返回的 error（经过重试等流程），例如由 InjectError 注入的错误
+	Err error
+}
+
+// Recorder 记录消息而不真正发送的 Bot
+//
+// 内部使用真实的 Bot，Message.Apply、中间件、HookAfterMessageApply、重试、Observer 等流程与真实发送完全一致，
+// 仅将 HTTP 请求替换为记录。Apply 等环节失败时不会记录
+type Recorder struct {
+	feishu.Bot
+
+	mu    sync.Mutex
+	calls []Call
+	errs  []error
+}
+
+// NewRecorder opts 为空时不启用限流；opts 中的 HTTPClient、Transport 会被替换
+//
+// 未指定 LimiterRegistry（或为 DefaultLimiterRegistry）时，每个 Recorder 使用独立的限流器，互不占用令牌
+func NewRecorder(opts *feishu.BotOptions) *Recorder {
+	var o feishu.BotOptions
+	if opts != nil {
+		o = *opts
+	} else {
+		o.SetLimiterPerSecond(-1)
+	}
+	if o.LimiterRegistry == nil || o.LimiterRegistry == feishu.DefaultLimiterRegistry {
+		o.LimiterRegistry = feishu.NewLimiterRegistry()
+	}
+
+	r := &Recorder{}
+	o.HTTPClient = &http.Client{Transport: recordTransport{r: r}}
+	o.Transport = nil
+	o.Middlewares = append([]feishu.Middleware{r.middleware}, o.Middlewares...)
+	r.Bot = feishu.NewBot("bottest", &o)
+	return r
+}
+
+type callStateKey struct{}
+
+type callState struct {
+	request *feishu.APIRequest
+}
+
+func (r *Recorder) middleware(next feishu.Sender) feishu.Sender {
+	return feishu.SenderFunc(func(ctx context.Context, msg feishu.Message) error {
+		state := &callState{}
+		err := next.SendMessage(context.WithValue(ctx, callStateKey{}, state), msg)
+
+		if state.request != nil {
+			r.mu.Lock()
+			r.calls = append(r.calls, Call{Body: state.request.MessageBody, Request: *state.request, Err: err})
+			r.mu.Unlock()
+		}
+		return err
+	})
+}
+
+func (r *Recorder) popError() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.errs) == 0 {
+		return nil
+	}
+	err := r.errs[0]
+	r.errs = r.errs[1:]
+	return err
+}
+
+// InjectError 按顺序为之后的每次 HTTP 请求（包括重试）指定结果，nil 表示成功
+//
+// *feishu.APIError 会被转换为对应的 HTTP 响应（StatusCode 为 0 时使用 200），由 Bot 按真实响应处理，
+// 例如 429、5xx 会触发 RetryPolicy；其他 error 作为网络错误由 http.Client 返回（包装为 *url.Error）
+func (r *Recorder) InjectError(errs ...error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errs = append(r.errs, errs...)
+}
+
+// Calls 所有发送记录
+func (r *Recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Messages 所有发送的消息
+func (r *Recorder) Messages() []feishu.MessageBody {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	bodies := make([]feishu.MessageBody, 0, len(r.calls))
+	for _, c := range r.calls {
+		bodies = append(bodies, c.Body)
+	}
+	return bodies
+}
+
+// Last 最后一次发送的消息
+func (r *Recorder) Last() (feishu.MessageBody, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.calls) == 0 {
+		return feishu.MessageBody{}, false
+	}
+	return r.calls[len(r.calls)-1].Body, true
+}
+
+// ByMsgType 指定类型的消息，例如 text、post、interactive
+func (r *Recorder) ByMsgType(msgType string) []feishu.MessageBody {
+	var bodies []feishu.MessageBody
+	for _, body := range r.Messages() {
+		if body.MsgType == msgType {
+			bodies = append(bodies, body)
+		}
+	}
+	return bodies
+}
+
+// Texts 所有文本消息的内容
+func (r *Recorder) Texts() []string {
+	var texts []string
+	for _, body := range r.ByMsgType("text") {
+		texts = append(texts, body.Content.Text)
+	}
+	return texts
+}
+
+// Reset 清空发送记录与尚未使用的 error
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls, r.errs = nil, nil
+}
+
+// --------------------------------------------------------------------------------
+
+type recordTransport struct {
+	r *Recorder
+}
+
+func (t recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	state, ok := req.Context().Value(callStateKey{}).(*callState)
+	if !ok {
+		return nil, errors.New("bottest: unexpected request")
+	}
+
+	raw, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	_ = req.Body.Close()
+
+	var apiReq feishu.APIRequest
+	if err := json.Unmarshal(raw, &apiReq); err != nil {
+		return nil, err
+	}
+	state.request = &apiReq
+
+	statusCode, body := http.StatusOK, []byte(`{"code":0,"msg":"success","data":{}}`)
+	if err := t.r.popError(); err != nil {
+		var apiErr *feishu.APIError
+		if !errors.As(err, &apiErr) {
+			return nil, err
+		}
+		if statusCode, body = apiErr.StatusCode, apiErr.Body; statusCode == 0 {
+			statusCode = http.StatusOK
+		}
+		if len(body) == 0 && (apiErr.Code != 0 || apiErr.Msg != "") {
+			if body, err = json.Marshal(feishu.APIResponse{Code: apiErr.Code, Msg: apiErr.Msg}); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return &http.Response{
+		StatusCode: statusCode,
+		Header:     http.Header{"Content-Type": []string{"application/json; charset=utf-8"}},
+		Body:       io.NopCloser(bytes.NewReader(body)),
+		Request:    req,
+	}, nil
+}
@@ -0,0 +1,215 @@
+package bottest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	feishu "github.com/electricbubble/feishu-bot-api/v2"
+)
+
+func TestRecorder(t *testing.T) {
+	opts := feishu.NewBotOptions().
+		SetLimiterPerSecond(-1).
+		SetSecretKey("secret").
+		SetHookAfterMessageApply(func(body *feishu.MessageBody) error {
+			if body.MsgType == "text" {
+				body.Content.Text += " (staging)"
+			}
+			return nil
+		})
+	r := NewRecorder(opts)
+
+	if _, ok := r.Last(); ok {
+		t.Fatal("Expected no messages")
+	}
+
+	if err := r.SendText("hi"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SendRichText(feishu.NewRichText(feishu.LanguageChinese, "title").Text("content", false)); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.SendCard(feishu.NewCardGlobalConfig(), feishu.NewCard(feishu.LanguageChinese, "card")); err != nil {
+		t.Fatal(err)
+	}
+
+	if n := len(r.Calls()); n != 3 {
+		t.Fatalf("Actual calls: %d, want: 3", n)
+	}
+	if c := r.Calls()[0]; c.Request.Sign == "" || c.Request.Timestamp == 0 {
+		t.Error("Expected the request to be signed")
+	}
+
+	if texts := r.Texts(); len(texts) != 1 || texts[0] != "hi (staging)" {
+		t.Errorf("Actual texts: %q, want: [\"hi (staging)\"]", texts)
+	}
+	text, err := DecodeText(r.ByMsgType("text")[0])
+	if err != nil || text != "hi (staging)" {
+		t.Errorf("Actual text: %q, %v", text, err)
+	}
+
+	post, err := DecodePost(r.ByMsgType("post")[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c := post["zh_cn"]; c.Title != "title" || c.Content[0][0]["text"] != "content" {
+		t.Errorf("Actual post: %+v", post)
+	}
+
+	last, _ := r.Last()
+	card, err := DecodeCard(last)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := card["header"]; !ok {
+		t.Errorf("Actual card: %v", card)
+	}
+	if _, err := DecodeText(last); err == nil {
+		t.Error("Expected an error for a card message")
+	}
+
+	r.Reset()
+	if n := len(r.Messages()); n != 0 {
+		t.Errorf("Actual messages: %d, want: 0", n)
+	}
+}
+
+func TestRecorder_InjectError(t *testing.T) {
+	r := NewRecorder(nil)
+
+	errSend := errors.New("send failed")
+	r.InjectError(errSend, nil)
+
+	if err := r.SendText("1"); !errors.Is(err, errSend) {
+		t.Errorf("Actual error: %v, want: %v", err, errSend)
+	}
+	if err := r.SendText("2"); err != nil {
+		t.Errorf("Actual error: %v, want: nil", err)
+	}
+	if err := r.SendText("3"); err != nil {
+		t.Errorf("Actual error: %v, want: nil", err)
+	}
+
+	calls := r.Calls()
+	if len(calls) != 3 {
+		t.Fatalf("Actual calls: %d, want: 3", len(calls))
+	}
+	if !errors.Is(calls[0].Err, errSend) || calls[1].Err != nil {
+		t.Errorf("Actual call errors: %v, %v", calls[0].Err, calls[1].Err)
+	}
+}
+
+type countingObserver struct {
+	feishu.NopObserver
+
+	mu       sync.Mutex
+	requests int
+	retries  int
+	drops    int
+}
+
+func (o *countingObserver) RequestSent(context.Context, feishu.RequestEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.requests++
+}
+
+func (o *countingObserver) Retry(context.Context, feishu.RetryEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.retries++
+}
+
+func (o *countingObserver) Drop(context.Context, feishu.DropEvent) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.drops++
+}
+
+func TestRecorder_InjectError_retry(t *testing.T) {
+	// 注入的错误经过真实的重试与 Observer 流程
+	obs := &countingObserver{}
+	r := NewRecorder(feishu.NewBotOptions().
+		SetLimiterPerSecond(-1).
+		SetObserver(obs).
+		SetRetry(feishu.NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond).SetJitter(0)))
+
+	r.InjectError(&feishu.APIError{StatusCode: http.StatusTooManyRequests}, &feishu.APIError{StatusCode: http.StatusServiceUnavailable}, nil)
+	if err := r.SendText("retried"); err != nil {
+		t.Fatal(err)
+	}
+	if obs.requests != 3 || obs.retries != 2 || obs.drops != 0 {
+		t.Errorf("Actual requests: %d, retries: %d, drops: %d, want: 3, 2, 0", obs.requests, obs.retries, obs.drops)
+	}
+
+	r.InjectError(&feishu.APIError{Code: feishu.CodeKeyWordsNotFound, Msg: "Key Words Not Found"})
+	err := r.SendText("dropped")
+	if !errors.Is(err, feishu.ErrKeywordNotMatched) {
+		t.Errorf("Actual error: %v, want: %v", err, feishu.ErrKeywordNotMatched)
+	}
+	if obs.requests != 4 || obs.retries != 2 || obs.drops != 1 {
+		t.Errorf("Actual requests: %d, retries: %d, drops: %d, want: 4, 2, 1", obs.requests, obs.retries, obs.drops)
+	}
+
+	calls := r.Calls()
+	if len(calls) != 2 {
+		t.Fatalf("Actual calls: %d, want: 2", len(calls))
+	}
+	if calls[0].Err != nil || !errors.Is(calls[1].Err, feishu.ErrKeywordNotMatched) {
+		t.Errorf("Actual call errors: %v, %v", calls[0].Err, calls[1].Err)
+	}
+}
+
+func TestRecorder_applyError(t *testing.T) {
+	errHook := errors.New("hook failed")
+	r := NewRecorder(feishu.NewBotOptions().SetLimiterPerSecond(-1).SetHookAfterMessageApply(func(*feishu.MessageBody) error {
+		return errHook
+	}))
+
+	if err := r.SendText("hi"); !errors.Is(err, errHook) {
+		t.Errorf("Actual error: %v, want: %v", err, errHook)
+	}
+	if n := len(r.Calls()); n != 0 {
+		t.Errorf("Actual calls: %d, want: 0", n)
+	}
+}
+
+func TestRecorder_privateLimiter(t *testing.T) {
+	// 每个 Recorder 的限流器相互独立，不会因为其他 Recorder 的发送而等待
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	for i := 0; i < 3; i++ {
+		r := NewRecorder(feishu.NewBotOptions())
+		for j := 0; j < 5; j++ {
+			if err := r.SendTextContext(ctx, "hi"); err != nil {
+				t.Fatalf("recorder(%d) send(%d): %v", i, j, err)
+			}
+		}
+	}
+}
+
+func TestRecorder_concurrent(t *testing.T) {
+	r := NewRecorder(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := r.SendText(strings.Repeat("x", 10)); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := len(r.Texts()); n != 50 {
+		t.Errorf("Actual texts: %d, want: 50", n)
+	}
+}
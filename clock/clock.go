@@ -0,0 +1,54 @@
+// Package clock 时钟抽象，用于签名时间戳、限流等场景，便于在测试中替换为可控的时钟
+package clock
+
+import "time"
+
+// Clock 时钟
+type Clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+	NewTimer(d time.Duration) Timer
+}
+
+// Timer 对应 time.Timer
+type Timer interface {
+	C() <-chan time.Time
+	Stop() bool
+	Reset(d time.Duration) bool
+}
+
+// System 系统时钟
+func System() Clock {
+	return systemClock{}
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) Sleep(d time.Duration)                  { time.Sleep(d) }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (systemClock) NewTimer(d time.Duration) Timer         { return systemTimer{time.NewTimer(d)} }
+
+type systemTimer struct {
+	t *time.Timer
+}
+
+func (t systemTimer) C() <-chan time.Time        { return t.t.C }
+func (t systemTimer) Stop() bool                 { return t.t.Stop() }
+func (t systemTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+// WithOffset 在 c 的基础上偏移 offset，用于修正本机时间与飞书服务器时间的偏差
+func WithOffset(c Clock, offset time.Duration) Clock {
+	if offset == 0 {
+		return c
+	}
+	return offsetClock{Clock: c, offset: offset}
+}
+
+type offsetClock struct {
+	Clock
+	offset time.Duration
+}
+
+func (c offsetClock) Now() time.Time { return c.Clock.Now().Add(c.offset) }
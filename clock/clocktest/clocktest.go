@@ -0,0 +1,158 @@
+// Package clocktest 测试用的时钟
+package clocktest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/electricbubble/feishu-bot-api/v2/clock"
+)
+
+var _ clock.Clock = (*FakeClock)(nil)
+
+// FakeClock 手动控制的时钟
+//
+// 时间只会在调用 Advance、Set 时前进；开启 AutoAdvance 后，创建定时器时会直接前进到定时器的触发时间，
+// 适用于单协程的场景，可以在几毫秒内完成原本需要等待数分钟的测试
+type FakeClock struct {
+	mu          sync.Mutex
+	now         time.Time
+	timers      []*fakeTimer
+	autoAdvance bool
+}
+
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// AutoAdvance 是否在创建定时器时自动前进到定时器的触发时间
+func (c *FakeClock) AutoAdvance(b bool) *FakeClock {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.autoAdvance = b
+	return c
+}
+
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *FakeClock) Sleep(d time.Duration) {
+	<-c.NewTimer(d).C()
+}
+
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	return c.NewTimer(d).C()
+}
+
+func (c *FakeClock) NewTimer(d time.Duration) clock.Timer {
+	t := &fakeTimer{clock: c, c: make(chan time.Time, 1)}
+
+	c.mu.Lock()
+	t.deadline = c.now.Add(d)
+	c.timers = append(c.timers, t)
+	if c.autoAdvance && t.deadline.After(c.now) {
+		c.now = t.deadline
+	}
+	c.fire()
+	c.mu.Unlock()
+
+	return t
+}
+
+// Advance 前进 d，并触发所有到期的定时器
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	c.fire()
+}
+
+// Set 设置当前时间，并触发所有到期的定时器
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = t
+	c.fire()
+}
+
+// Waiters 尚未触发的定时器数量
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.timers)
+}
+
+// Next 最早的未触发定时器的触发时间，没有定时器时 ok 为 false
+func (c *FakeClock) Next() (deadline time.Time, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, t := range c.timers {
+		if !ok || t.deadline.Before(deadline) {
+			deadline, ok = t.deadline, true
+		}
+	}
+	return deadline, ok
+}
+
+// fire 按触发时间顺序触发所有到期的定时器，调用方需持有 c.mu
+func (c *FakeClock) fire() {
+	sort.SliceStable(c.timers, func(i, j int) bool { return c.timers[i].deadline.Before(c.timers[j].deadline) })
+
+	n := 0
+	for _, t := range c.timers {
+		if t.deadline.After(c.now) {
+			break
+		}
+		select {
+		case t.c <- c.now:
+		default:
+		}
+		n++
+	}
+	c.timers = c.timers[n:]
+}
+
+func (c *FakeClock) remove(t *fakeTimer) bool {
+	for i := range c.timers {
+		if c.timers[i] == t {
+			c.timers = append(c.timers[:i], c.timers[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+type fakeTimer struct {
+	clock    *FakeClock
+	deadline time.Time
+	c        chan time.Time
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	return t.clock.remove(t)
+}
+
+func (t *fakeTimer) Reset(d time.Duration) bool {
+	c := t.clock
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	active := c.remove(t)
+	t.deadline = c.now.Add(d)
+	c.timers = append(c.timers, t)
+	if c.autoAdvance && t.deadline.After(c.now) {
+		c.now = t.deadline
+	}
+	c.fire()
+	return active
+}
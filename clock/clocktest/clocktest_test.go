@@ -0,0 +1,59 @@
+package clocktest
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFakeClock(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("advance", func(t *testing.T) {
+		c := NewFakeClock(start)
+		t1 := c.NewTimer(time.Second)
+		t2 := c.NewTimer(2 * time.Second)
+
+		c.Advance(time.Second)
+		select {
+		case now := <-t1.C():
+			if !now.Equal(start.Add(time.Second)) {
+				t.Errorf("Actual: %s, want: %s", now, start.Add(time.Second))
+			}
+		default:
+			t.Fatal("Expected t1 to fire")
+		}
+		if n := c.Waiters(); n != 1 {
+			t.Errorf("Actual waiters: %d, want: 1", n)
+		}
+
+		if !t2.Stop() {
+			t.Error("Expected t2 to be active")
+		}
+		c.Advance(time.Second)
+		select {
+		case <-t2.C():
+			t.Error("Expected stopped timer not to fire")
+		default:
+		}
+	})
+
+	t.Run("auto_advance", func(t *testing.T) {
+		c := NewFakeClock(start).AutoAdvance(true)
+		c.Sleep(time.Minute)
+		if d := c.Now().Sub(start); d != time.Minute {
+			t.Errorf("Actual: %s, want: %s", d, time.Minute)
+		}
+	})
+
+	t.Run("next", func(t *testing.T) {
+		c := NewFakeClock(start)
+		if _, ok := c.Next(); ok {
+			t.Error("Expected no timers")
+		}
+		c.NewTimer(3 * time.Second)
+		c.NewTimer(time.Second)
+		if next, _ := c.Next(); !next.Equal(start.Add(time.Second)) {
+			t.Errorf("Actual: %s, want: %s", next, start.Add(time.Second))
+		}
+	})
+}
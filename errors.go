@@ -0,0 +1,114 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 自定义机器人常见错误码
+//
+// https://open.feishu.cn/document/client-docs/bot-v3/add-custom-bot
+const (
+	// CodeTooManyRequest 请求过于频繁（too many request）
+	CodeTooManyRequest = 9499
+	// CodeFrequencyLimited 触发频率限制（frequency limited）
+	CodeFrequencyLimited = 11232
+	// CodeTokenInvalid webhook 地址无效，通常是机器人已被移出群组或已被删除（incoming webhook access token invalid）
+	CodeTokenInvalid = 19001
+	// CodeParamsError 请求参数错误，例如缺少 msg_type 或消息内容格式错误（params error）
+	CodeParamsError = 19002
+	// CodeBotNotEnabled 机器人已被停用（Bot Not Enabled）
+	CodeBotNotEnabled = 19007
+	// CodeSignMatchFail 签名校验失败，或者时间戳距发送时已超过 1 小时（sign match fail or timestamp is not within one hour from current time）
+	CodeSignMatchFail = 19021
+	// CodeIPNotAllowed 请求来源 IP 不在白名单中（Ip Not Allowed）
+	CodeIPNotAllowed = 19022
+	// CodeKeyWordsNotFound 消息中未包含设置的自定义关键词（Key Words Not Found）
+	CodeKeyWordsNotFound = 19024
+	// CodeMessageTooLong 消息内容超出长度限制
+	CodeMessageTooLong = 230025
+)
+
+var (
+	// ErrRateLimited 触发了飞书的频率限制，稍后可以重试
+	ErrRateLimited = errors.New("rate limited")
+
+	// ErrSignatureMismatch 签名校验失败，需要检查 SecretKey
+	ErrSignatureMismatch = errors.New("signature mismatch")
+
+	// ErrTimestampExpired 时间戳距发送时已超过 1 小时，需要检查本机时间
+	//
+	// 飞书对签名失败与时间戳过期返回同一个错误码（CodeSignMatchFail），因此该错误与 ErrSignatureMismatch 总是同时匹配
+	ErrTimestampExpired = errors.New("timestamp expired")
+
+	// ErrKeywordNotMatched 消息中未包含自定义关键词
+	ErrKeywordNotMatched = errors.New("keyword not matched")
+
+	// ErrIPNotAllowed 请求来源 IP 不在白名单中
+	ErrIPNotAllowed = errors.New("ip not allowed")
+
+	// ErrBotDisabled 机器人已被移除或停用
+	ErrBotDisabled = errors.New("bot removed or disabled")
+
+	// ErrRequestTooLarge 请求体过大
+	ErrRequestTooLarge = errors.New("request too large")
+)
+
+// APIError webhook 接口返回的错误（code 不为 0，或者 HTTP 状态码异常）
+//
+// 可以通过 errors.Is 判断具体的错误类型：
+//   - ErrRateLimited
+//   - ErrSignatureMismatch
+//   - ErrTimestampExpired
+//   - ErrKeywordNotMatched
+//   - ErrIPNotAllowed
+//   - ErrBotDisabled
+//   - ErrRequestTooLarge
+type APIError struct {
+	// HTTP 状态码
+	StatusCode int
+
+	// 飞书错误码
+	Code int
+
+	// 飞书错误信息
+	Msg string
+
+	// 原始响应内容
+	Body []byte
+}
+
+func newAPIError(statusCode int, resp APIResponse, body []byte) *APIError {
+	return &APIError{
+		StatusCode: statusCode,
+		Code:       resp.Code,
+		Msg:        resp.Msg,
+		Body:       body,
+	}
+}
+
+func (e *APIError) Error() string {
+	if e.Code == 0 && len(e.Body) == 0 {
+		return fmt.Sprintf("api error: http status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("api error: %s", e.Body)
+}
+
+func (e *APIError) Is(target error) bool {
+	switch target {
+	case ErrRateLimited:
+		return e.Code == CodeTooManyRequest || e.Code == CodeFrequencyLimited || e.StatusCode == http.StatusTooManyRequests
+	case ErrSignatureMismatch, ErrTimestampExpired:
+		return e.Code == CodeSignMatchFail
+	case ErrKeywordNotMatched:
+		return e.Code == CodeKeyWordsNotFound
+	case ErrIPNotAllowed:
+		return e.Code == CodeIPNotAllowed
+	case ErrBotDisabled:
+		return e.Code == CodeTokenInvalid || e.Code == CodeBotNotEnabled
+	case ErrRequestTooLarge:
+		return e.Code == CodeMessageTooLong || e.StatusCode == http.StatusRequestEntityTooLarge
+	}
+	return false
+}
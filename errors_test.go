@@ -0,0 +1,132 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestAPIError_Is(t *testing.T) {
+	sentinels := []error{
+		ErrRateLimited,
+		ErrSignatureMismatch,
+		ErrTimestampExpired,
+		ErrKeywordNotMatched,
+		ErrIPNotAllowed,
+		ErrBotDisabled,
+		ErrRequestTooLarge,
+	}
+
+	tests := []struct {
+		name string
+		err  *APIError
+		want []error
+	}{
+		{
+			name: "too_many_request",
+			err:  &APIError{StatusCode: http.StatusOK, Code: CodeTooManyRequest},
+			want: []error{ErrRateLimited},
+		},
+		{
+			name: "frequency_limited",
+			err:  &APIError{StatusCode: http.StatusOK, Code: CodeFrequencyLimited},
+			want: []error{ErrRateLimited},
+		},
+		{
+			name: "http_429",
+			err:  &APIError{StatusCode: http.StatusTooManyRequests},
+			want: []error{ErrRateLimited},
+		},
+		{
+			name: "sign_match_fail",
+			err:  &APIError{StatusCode: http.StatusOK, Code: CodeSignMatchFail},
+			want: []error{ErrSignatureMismatch, ErrTimestampExpired},
+		},
+		{
+			name: "key_words_not_found",
+			err:  &APIError{StatusCode: http.StatusOK, Code: CodeKeyWordsNotFound},
+			want: []error{ErrKeywordNotMatched},
+		},
+		{
+			name: "ip_not_allowed",
+			err:  &APIError{StatusCode: http.StatusOK, Code: CodeIPNotAllowed},
+			want: []error{ErrIPNotAllowed},
+		},
+		{
+			name: "token_invalid",
+			err:  &APIError{StatusCode: http.StatusOK, Code: CodeTokenInvalid},
+			want: []error{ErrBotDisabled},
+		},
+		{
+			name: "bot_not_enabled",
+			err:  &APIError{StatusCode: http.StatusOK, Code: CodeBotNotEnabled},
+			want: []error{ErrBotDisabled},
+		},
+		{
+			name: "http_413",
+			err:  &APIError{StatusCode: http.StatusRequestEntityTooLarge},
+			want: []error{ErrRequestTooLarge},
+		},
+		{
+			name: "unknown",
+			err:  &APIError{StatusCode: http.StatusOK, Code: 1},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, target := range sentinels {
+				want := false
+				for _, w := range tt.want {
+					if w == target {
+						want = true
+					}
+				}
+				if got := errors.Is(tt.err, target); got != want {
+					t.Errorf("errors.Is(%v) = %v, want %v", target, got, want)
+				}
+			}
+		})
+	}
+}
+
+func Test_bot_SendMessage_APIError(t *testing.T) {
+	t.Run("code", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"code":19024,"msg":"Key Words Not Found","data":{}}`))
+		}))
+		defer srv.Close()
+
+		err := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())).SendText("hi")
+		if !errors.Is(err, ErrKeywordNotMatched) {
+			t.Fatalf("Actual error: %v, want: %v", err, ErrKeywordNotMatched)
+		}
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Actual error type: %T, want: %T", err, apiErr)
+		}
+		if apiErr.StatusCode != http.StatusOK || apiErr.Code != CodeKeyWordsNotFound || apiErr.Msg != "Key Words Not Found" {
+			t.Errorf("Actual: %+v", apiErr)
+		}
+	})
+
+	t.Run("http_status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte(`<html>bad gateway</html>`))
+		}))
+		defer srv.Close()
+
+		err := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())).SendText("hi")
+
+		var apiErr *APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("Actual error: %v, want: %T", err, apiErr)
+		}
+		if apiErr.StatusCode != http.StatusBadGateway || string(apiErr.Body) != `<html>bad gateway</html>` {
+			t.Errorf("Actual: %+v", apiErr)
+		}
+	})
+}
@@ -0,0 +1,318 @@
+// Package feishutest 本地模拟的飞书自定义机器人 webhook 服务，用于离线测试完整的发送流程（签名、限流等）
+package feishutest
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	feishu "github.com/electricbubble/feishu-bot-api/v2"
+	"github.com/electricbubble/feishu-bot-api/v2/clock"
+)
+
+// MaxBodySize 请求体大小上限
+const MaxBodySize = 20 << 10
+
+// BotConfig 单个机器人的安全设置与限流配置
+//
+// https://open.feishu.cn/document/client-docs/bot-v3/add-custom-bot
+type BotConfig struct {
+	// SecretKey 签名校验，为空时不校验
+	SecretKey string
+	// Keywords 自定义关键词，消息中至少包含其中一个，为空时不校验
+	Keywords []string
+	// PerSecond、PerMinute 每秒、每分钟的请求数上限，默认为 5、100，小于 0 时不限制
+	PerSecond, PerMinute int
+	// Disabled 机器人已停用
+	Disabled bool
+}
+
+// Request 收到的请求
+type Request struct {
+	Token string
+	// Body 请求内容，请求体无法解析时为零值
+	Body feishu.APIRequest
+	Raw  []byte
+	// Code 返回的飞书错误码，0 表示成功
+	Code int
+	// LogID 响应头 X-Tt-Logid
+	LogID string
+}
+
+// Server 模拟的 webhook 服务
+type Server struct {
+	*httptest.Server
+
+	clock clock.Clock
+
+	mu       sync.Mutex
+	bots     map[string]*botState
+	requests []Request
+	logID    uint64
+}
+
+type botState struct {
+	cfg                      BotConfig
+	second, minute           int64
+	secondCount, minuteCount int
+}
+
+// NewServer c 为空时使用系统时钟，用于校验签名时间戳与限流
+func NewServer(c clock.Clock) *Server {
+	if c == nil {
+		c = clock.System()
+	}
+	s := &Server{clock: c, bots: make(map[string]*botState)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// AddBot 注册机器人，返回对应的 webhook 地址。未注册的 token 返回 CodeTokenInvalid
+//
+// token 需要是 UUID 格式（与真实的 webhook 一致），NewBot 才能从 webhook 地址中解析出本服务的地址，否则需要设置 BotOptions.BaseURL
+func (s *Server) AddBot(token string, cfg BotConfig) string {
+	if cfg.PerSecond == 0 {
+		cfg.PerSecond = 5
+	}
+	if cfg.PerMinute == 0 {
+		cfg.PerMinute = 100
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bots[token] = &botState{cfg: cfg}
+	return s.Webhook(token)
+}
+
+// Webhook token 对应的 webhook 地址
+func (s *Server) Webhook(token string) string {
+	return s.URL + "/open-apis/bot/v2/hook/" + token
+}
+
+// Requests 收到的所有请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Messages token 对应的机器人成功收到的消息
+func (s *Server) Messages(token string) []feishu.MessageBody {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var bodies []feishu.MessageBody
+	for _, r := range s.requests {
+		if r.Token == token && r.Code == 0 {
+			bodies = append(bodies, r.Body.MessageBody)
+		}
+	}
+	return bodies
+}
+
+// Reset 清空收到的请求与限流计数
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests = nil
+	for _, b := range s.bots {
+		b.second, b.minute, b.secondCount, b.minuteCount = 0, 0, 0, 0
+	}
+}
+
+// --------------------------------------------------------------------------------
+
+var _hookPathRegexp = regexp.MustCompile(`^/open-apis/bot/v2/hook/([^/]+)$`)
+
+type response struct {
+	status int
+	code   int
+	msg    string
+}
+
+var (
+	respSuccess         = response{http.StatusOK, 0, "success"}
+	respBadRequest      = response{http.StatusBadRequest, feishu.CodeParamsError, "Bad Request"}
+	respTokenInvalid    = response{http.StatusOK, feishu.CodeTokenInvalid, "param invalid: incoming webhook access token invalid"}
+	respBotNotEnabled   = response{http.StatusOK, feishu.CodeBotNotEnabled, "Bot Not Enabled"}
+	respFrequency       = response{http.StatusOK, feishu.CodeFrequencyLimited, "frequency limited"}
+	respSignMatchFail   = response{http.StatusOK, feishu.CodeSignMatchFail, "sign match fail or timestamp is not within one hour from current time"}
+	respKeyWordNotFound = response{http.StatusOK, feishu.CodeKeyWordsNotFound, "Key Words Not Found"}
+	respTooLong         = response{http.StatusOK, feishu.CodeMessageTooLong, "request body too large"}
+)
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	m := _hookPathRegexp.FindStringSubmatch(r.URL.Path)
+	if m == nil || r.Method != http.MethodPost {
+		http.NotFound(w, r)
+		return
+	}
+
+	raw, err := io.ReadAll(io.LimitReader(r.Body, MaxBodySize+1))
+	if err != nil {
+		writeResponse(w, respBadRequest)
+		return
+	}
+
+	req := Request{Token: m[1], Raw: raw}
+	decodeErr := json.Unmarshal(raw, &req.Body)
+
+	resp := s.check(req.Token, len(raw), &req.Body, decodeErr)
+	req.Code = resp.code
+
+	s.mu.Lock()
+	s.logID++
+	req.LogID = fmt.Sprintf("%s%06d", s.clock.Now().UTC().Format("20060102150405"), s.logID)
+	s.requests = append(s.requests, req)
+	s.mu.Unlock()
+
+	w.Header().Set("X-Tt-Logid", req.LogID)
+	writeResponse(w, resp)
+}
+
+func (s *Server) check(token string, size int, body *feishu.APIRequest, decodeErr error) response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b, ok := s.bots[token]
+	if !ok {
+		return respTokenInvalid
+	}
+	if b.cfg.Disabled {
+		return respBotNotEnabled
+	}
+
+	now := s.clock.Now()
+	if !b.allow(now) {
+		return respFrequency
+	}
+
+	if size > MaxBodySize {
+		return respTooLong
+	}
+	if decodeErr != nil {
+		return respBadRequest
+	}
+
+	if b.cfg.SecretKey != "" && !verifySignature(body.Timestamp, body.Sign, b.cfg.SecretKey, now) {
+		return respSignMatchFail
+	}
+
+	if !validateBody(body.MessageBody) {
+		return response{http.StatusOK, feishu.CodeParamsError, "params error, invalid " + body.MsgType + " message"}
+	}
+
+	if len(b.cfg.Keywords) > 0 && !containsKeyword(body.MessageBody, b.cfg.Keywords) {
+		return respKeyWordNotFound
+	}
+
+	return respSuccess
+}
+
+// allow 按自然秒、自然分钟计数
+func (b *botState) allow(now time.Time) bool {
+	if sec := now.Unix(); b.second != sec {
+		b.second, b.secondCount = sec, 0
+	}
+	if m := now.Unix() - int64(now.Second()); b.minute != m {
+		b.minute, b.minuteCount = m, 0
+	}
+
+	if (b.cfg.PerSecond > 0 && b.secondCount >= b.cfg.PerSecond) || (b.cfg.PerMinute > 0 && b.minuteCount >= b.cfg.PerMinute) {
+		return false
+	}
+	b.secondCount++
+	b.minuteCount++
+	return true
+}
+
+// verifySignature 签名字符串为 timestamp + "\n" + 密钥，使用 HmacSHA256 计算签名后 Base64 编码；时间戳与当前时间相差不能超过 1 小时
+func verifySignature(timestamp int64, sign, secretKey string, now time.Time) bool {
+	if timestamp == 0 || sign == "" {
+		return false
+	}
+	if d := now.Sub(time.Unix(timestamp, 0)); d > time.Hour || d < -time.Hour {
+		return false
+	}
+
+	h := hmac.New(sha256.New, []byte(strconv.FormatInt(timestamp, 10)+"\n"+secretKey))
+	want := base64.StdEncoding.EncodeToString(h.Sum(nil))
+	return hmac.Equal([]byte(sign), []byte(want))
+}
+
+// validateBody 校验各类消息必填的字段
+func validateBody(body feishu.MessageBody) bool {
+	switch body.MsgType {
+	case "text":
+		return body.Content != nil && body.Content.Text != ""
+	case "post":
+		var post map[string]struct {
+			Content [][]struct {
+				Tag string `json:"tag"`
+			} `json:"content"`
+		}
+		if body.Content == nil || body.Content.Post == nil || json.Unmarshal(*body.Content.Post, &post) != nil || len(post) == 0 {
+			return false
+		}
+		for _, lang := range post {
+			for _, paragraph := range lang.Content {
+				for _, lbl := range paragraph {
+					if lbl.Tag == "" {
+						return false
+					}
+				}
+			}
+		}
+		return true
+	case "image":
+		return body.Content != nil && body.Content.ImageKey != ""
+	case "share_chat":
+		return body.Content != nil && body.Content.ShareChatID != ""
+	case "interactive":
+		var card map[string]json.RawMessage
+		return body.Card != nil && json.Unmarshal(*body.Card, &card) == nil && len(card) > 0
+	default:
+		return false
+	}
+}
+
+// containsKeyword 在消息的文本内容中查找关键词
+func containsKeyword(body feishu.MessageBody, keywords []string) bool {
+	var text string
+	switch {
+	case body.Card != nil:
+		text = string(*body.Card)
+	case body.Content != nil && body.Content.Post != nil:
+		text = string(*body.Content.Post)
+	case body.Content != nil:
+		text = body.Content.Text
+	}
+
+	for _, kw := range keywords {
+		if kw != "" && strings.Contains(text, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeResponse(w http.ResponseWriter, resp response) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(resp.status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"code": resp.code,
+		"msg":  resp.msg,
+		"data": map[string]any{},
+	})
+}
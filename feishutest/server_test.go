@@ -0,0 +1,184 @@
+package feishutest
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	feishu "github.com/electricbubble/feishu-bot-api/v2"
+	"github.com/electricbubble/feishu-bot-api/v2/clock/clocktest"
+)
+
+const token = "0d4e8b4a-1c2f-4b7e-9a3d-5f6e7a8b9c0d"
+
+func newBotOptions() *feishu.BotOptions {
+	return feishu.NewBotOptions().SetLimiterRegistry(feishu.NewLimiterRegistry())
+}
+
+func TestServer(t *testing.T) {
+	clk := clocktest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	s := NewServer(clk)
+	defer s.Close()
+
+	tests := []struct {
+		name    string
+		cfg     BotConfig
+		opts    *feishu.BotOptions
+		send    func(b feishu.Bot) error
+		wantErr error
+	}{
+		{
+			name: "success",
+			cfg:  BotConfig{SecretKey: "secret", Keywords: []string{"alert"}},
+			opts: newBotOptions().SetSecretKey("secret").SetClock(clk),
+			send: func(b feishu.Bot) error { return b.SendText("alert: disk full") },
+		},
+		{
+			name:    "signature_mismatch",
+			cfg:     BotConfig{SecretKey: "secret"},
+			opts:    newBotOptions().SetSecretKey("wrong"),
+			send:    func(b feishu.Bot) error { return b.SendText("hi") },
+			wantErr: feishu.ErrSignatureMismatch,
+		},
+		{
+			name:    "signature_missing",
+			cfg:     BotConfig{SecretKey: "secret"},
+			opts:    newBotOptions(),
+			send:    func(b feishu.Bot) error { return b.SendText("hi") },
+			wantErr: feishu.ErrSignatureMismatch,
+		},
+		{
+			name:    "timestamp_expired",
+			cfg:     BotConfig{SecretKey: "secret"},
+			opts:    newBotOptions().SetSecretKey("secret").SetClock(clk).SetClockSkew(-61 * time.Minute),
+			send:    func(b feishu.Bot) error { return b.SendText("hi") },
+			wantErr: feishu.ErrTimestampExpired,
+		},
+		{
+			name: "timestamp_within_window",
+			cfg:  BotConfig{SecretKey: "secret"},
+			opts: newBotOptions().SetSecretKey("secret").SetClock(clk).SetClockSkew(59 * time.Minute),
+			send: func(b feishu.Bot) error { return b.SendText("hi") },
+		},
+		{
+			name:    "keyword_not_matched",
+			cfg:     BotConfig{Keywords: []string{"alert"}},
+			opts:    newBotOptions(),
+			send:    func(b feishu.Bot) error { return b.SendText("hi") },
+			wantErr: feishu.ErrKeywordNotMatched,
+		},
+		{
+			name: "keyword_in_post",
+			cfg:  BotConfig{Keywords: []string{"alert"}},
+			opts: newBotOptions(),
+			send: func(b feishu.Bot) error {
+				return b.SendRichText(feishu.NewRichText(feishu.LanguageChinese, "alert").Text("disk full", false))
+			},
+		},
+		{
+			name:    "bot_disabled",
+			cfg:     BotConfig{Disabled: true},
+			opts:    newBotOptions(),
+			send:    func(b feishu.Bot) error { return b.SendText("hi") },
+			wantErr: feishu.ErrBotDisabled,
+		},
+		{
+			name:    "request_too_large",
+			opts:    newBotOptions(),
+			send:    func(b feishu.Bot) error { return b.SendText(strings.Repeat("x", MaxBodySize)) },
+			wantErr: feishu.ErrRequestTooLarge,
+		},
+		{
+			name:    "invalid_body",
+			opts:    newBotOptions(),
+			send:    func(b feishu.Bot) error { return b.SendImage("") },
+			wantErr: &feishu.APIError{Code: feishu.CodeParamsError},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s.Reset()
+			b := feishu.NewBot(s.AddBot(token, tt.cfg), tt.opts)
+
+			err := tt.send(b)
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Fatalf("Received unexpected error:\n%+v", err)
+				}
+				if n := len(s.Messages(token)); n != 1 {
+					t.Errorf("Actual messages: %d, want: 1", n)
+				}
+			case *feishu.APIError:
+				var apiErr *feishu.APIError
+				if !errors.As(err, &apiErr) || apiErr.Code != want.Code {
+					t.Fatalf("Actual error: %v, want code: %d", err, want.Code)
+				}
+			default:
+				if !errors.Is(err, want) {
+					t.Fatalf("Actual error: %v, want: %v", err, want)
+				}
+				if n := len(s.Messages(token)); n != 0 {
+					t.Errorf("Actual messages: %d, want: 0", n)
+				}
+			}
+		})
+	}
+}
+
+func TestServer_tokenInvalid(t *testing.T) {
+	s := NewServer(nil)
+	defer s.Close()
+
+	err := feishu.NewBot(s.Webhook(token), newBotOptions()).SendText("hi")
+	var apiErr *feishu.APIError
+	if !errors.As(err, &apiErr) || apiErr.Code != feishu.CodeTokenInvalid {
+		t.Fatalf("Actual error: %v, want code: %d", err, feishu.CodeTokenInvalid)
+	}
+	if reqs := s.Requests(); len(reqs) != 1 || reqs[0].Code != feishu.CodeTokenInvalid {
+		t.Errorf("Actual requests: %+v", reqs)
+	}
+}
+
+func TestServer_rateLimit(t *testing.T) {
+	clk := clocktest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)).AutoAdvance(true)
+	s := NewServer(clk)
+	defer s.Close()
+	webhook := s.AddBot(token, BotConfig{PerSecond: 2, PerMinute: 5})
+
+	t.Run("without_limiter", func(t *testing.T) {
+		s.Reset()
+		b := feishu.NewBot(webhook, newBotOptions().SetLimiterPerSecond(-1))
+		requireNoError(t, b.SendText("1"))
+		requireNoError(t, b.SendText("2"))
+		if err := b.SendText("3"); !errors.Is(err, feishu.ErrRateLimited) {
+			t.Fatalf("Actual error: %v, want: %v", err, feishu.ErrRateLimited)
+		}
+	})
+
+	t.Run("with_limiter", func(t *testing.T) {
+		s.Reset()
+		clk.Advance(time.Minute)
+		b := feishu.NewBot(webhook, newBotOptions().SetClock(clk).SetLimiterPerSecond(2).SetLimiterPerMinute(5))
+
+		start := clk.Now()
+		for i := 0; i < 6; i++ {
+			requireNoError(t, b.SendText("hi"))
+		}
+		if n := len(s.Messages(token)); n != 6 {
+			t.Errorf("Actual messages: %d, want: 6", n)
+		}
+		if d := clk.Now().Sub(start); d != time.Minute {
+			t.Errorf("Actual duration: %s, want: %s", d, time.Minute)
+		}
+	})
+}
+
+func requireNoError(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("Received unexpected error:\n%+v", err)
+	}
+}
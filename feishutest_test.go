@@ -0,0 +1,79 @@
+package feishu_bot_api_test
+
+import (
+	"testing"
+
+	feishu "github.com/electricbubble/feishu-bot-api/v2"
+	"github.com/electricbubble/feishu-bot-api/v2/feishutest"
+)
+
+// TestBot_feishutest 使用本地模拟的 webhook 服务离线测试完整的发送流程
+func TestBot_feishutest(t *testing.T) {
+	const token = "0d4e8b4a-1c2f-4b7e-9a3d-5f6e7a8b9c0d"
+
+	s := feishutest.NewServer(nil)
+	defer s.Close()
+
+	b := feishu.NewBot(
+		s.AddBot(token, feishutest.BotConfig{SecretKey: "secret"}),
+		feishu.NewBotOptions().SetSecretKey("secret").SetLimiterRegistry(feishu.NewLimiterRegistry()),
+	)
+
+	tests := []struct {
+		name    string
+		send    func() error
+		msgType string
+	}{
+		{
+			name:    "text",
+			send:    func() error { return b.SendText("hi") },
+			msgType: "text",
+		},
+		{
+			name: "rich_text",
+			send: func() error {
+				return b.SendRichText(
+					feishu.NewRichText(feishu.LanguageChinese, "标题").Text("内容", false).Hyperlink("链接", "https://open.feishu.cn"),
+					feishu.NewRichText(feishu.LanguageEnglish, "title").Text("content", false),
+				)
+			},
+			msgType: "post",
+		},
+		{
+			name:    "image",
+			send:    func() error { return b.SendImage("img_ecffc3b9-8f14-400f-a014-05eca1a4310g") },
+			msgType: "image",
+		},
+		{
+			name:    "group_business_card",
+			send:    func() error { return b.SendGroupBusinessCard("oc_f5b1a7eb27ae2c7b6adc2a74faf339ff") },
+			msgType: "share_chat",
+		},
+		{
+			name: "card",
+			send: func() error {
+				return b.SendCard(feishu.NewCardGlobalConfig(), feishu.NewCard(feishu.LanguageChinese, "标题").
+					Elements([]feishu.CardElement{feishu.NewCardElementMarkdown("**内容**")}))
+			},
+			msgType: "interactive",
+		},
+		{
+			name:    "card_via_template",
+			send:    func() error { return b.SendCardViaTemplate("ctp_AAr5sNHRSO6S", map[string]any{"name": "feishu"}) },
+			msgType: "interactive",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s.Reset()
+			if err := tt.send(); err != nil {
+				t.Fatalf("Received unexpected error:\n%+v", err)
+			}
+			msgs := s.Messages(token)
+			if len(msgs) != 1 || msgs[0].MsgType != tt.msgType {
+				t.Errorf("Actual messages: %+v, want msg_type: %s", msgs, tt.msgType)
+			}
+		})
+	}
+}
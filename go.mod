@@ -2,7 +2,4 @@ module github.com/electricbubble/feishu-bot-api/v2
 
 go 1.21.0
 
-require (
-	github.com/electricbubble/xhttpclient v0.5.1
-	golang.org/x/time v0.5.0
-)
+require golang.org/x/time v0.5.0
@@ -0,0 +1,72 @@
+package feishu_bot_api
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// sharedTransports 未自定义 TLSConfig 时，相同配置的 Bot 共享同一个 http.Transport，以复用连接
+var sharedTransports = struct {
+	mu         sync.Mutex
+	transports map[transportKey]*http.Transport
+}{transports: make(map[transportKey]*http.Transport)}
+
+type transportKey struct {
+	connectTimeout      time.Duration
+	useEnvironmentProxy bool
+}
+
+// httpClient 根据 BotOptions 创建 HTTP 客户端
+//
+// 优先级：HTTPClient > Transport > 根据 ConnectTimeout、TLSConfig、UseEnvironmentProxy 创建的 http.Transport
+func (opts *BotOptions) httpClient() *http.Client {
+	if opts.HTTPClient != nil {
+		return opts.HTTPClient
+	}
+
+	if opts.Transport != nil {
+		return &http.Client{Transport: opts.Transport}
+	}
+
+	if opts.TLSConfig != nil {
+		return &http.Client{Transport: opts.newTransport()}
+	}
+
+	key := transportKey{connectTimeout: opts.ConnectTimeout, useEnvironmentProxy: opts.UseEnvironmentProxy}
+
+	sharedTransports.mu.Lock()
+	defer sharedTransports.mu.Unlock()
+
+	tr, ok := sharedTransports.transports[key]
+	if !ok {
+		tr = opts.newTransport()
+		sharedTransports.transports[key] = tr
+	}
+	return &http.Client{Transport: tr}
+}
+
+// newTransport 参考 http.DefaultTransport
+func (opts *BotOptions) newTransport() *http.Transport {
+	dialer := &net.Dialer{KeepAlive: 30 * time.Second}
+	if opts.ConnectTimeout > 0 {
+		dialer.Timeout = opts.ConnectTimeout
+	}
+
+	tr := &http.Transport{
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          100,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	if opts.TLSConfig != nil {
+		tr.TLSClientConfig = opts.TLSConfig.Clone()
+	}
+	if opts.UseEnvironmentProxy {
+		tr.Proxy = http.ProxyFromEnvironment
+	}
+	return tr
+}
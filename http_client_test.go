@@ -0,0 +1,115 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type countingRoundTripper struct {
+	n atomic.Int32
+}
+
+func (rt *countingRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	rt.n.Add(1)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestBotOptions_httpClient(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+	defer srv.Close()
+
+	newOpts := func() *BotOptions {
+		return NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())
+	}
+
+	t.Run("transport", func(t *testing.T) {
+		rt := new(countingRoundTripper)
+		requireNoError(t, NewBot("tmp", newOpts().SetTransport(rt)).SendText("hi"))
+		if n := rt.n.Load(); n != 1 {
+			t.Errorf("Actual round trips: %d, want: 1", n)
+		}
+	})
+
+	t.Run("http_client", func(t *testing.T) {
+		rt := new(countingRoundTripper)
+		cli := &http.Client{Transport: rt}
+		b := NewBot("tmp", newOpts().SetHTTPClient(cli).SetTransport(http.DefaultTransport)).(*bot)
+		if b.cli != cli {
+			t.Fatal("Expected BotOptions.HTTPClient to take precedence")
+		}
+		requireNoError(t, b.SendText("hi"))
+		if n := rt.n.Load(); n != 1 {
+			t.Errorf("Actual round trips: %d, want: 1", n)
+		}
+	})
+
+	t.Run("shared_transport", func(t *testing.T) {
+		b1 := NewBot("tmp", newOpts()).(*bot)
+		b2 := NewBot("tmp", newOpts()).(*bot)
+		b3 := NewBot("tmp", newOpts().SetConnectTimeout(time.Second)).(*bot)
+		if b1.cli.Transport != b2.cli.Transport {
+			t.Error("Expected bots with the same options to share the transport")
+		}
+		if b1.cli.Transport == b3.cli.Transport {
+			t.Error("Expected bots with different options not to share the transport")
+		}
+	})
+
+	t.Run("environment_proxy", func(t *testing.T) {
+		if tr := NewBot("tmp", newOpts()).(*bot).cli.Transport.(*http.Transport); tr.Proxy != nil {
+			t.Error("Expected the environment proxy to be opt-in")
+		}
+		if tr := NewBot("tmp", newOpts().SetUseEnvironmentProxy(true)).(*bot).cli.Transport.(*http.Transport); tr.Proxy == nil {
+			t.Error("Expected the environment proxy to be used")
+		}
+	})
+}
+
+func TestBotOptions_TLSConfig(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+	defer srv.Close()
+
+	opts := NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())
+	if err := NewBot("tmp", opts).SendText("hi"); err == nil {
+		t.Fatal("Expected an error for the untrusted certificate")
+	}
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+	opts = NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetTLSConfig(&tls.Config{RootCAs: pool})
+	requireNoError(t, NewBot("tmp", opts).SendText("hi"))
+}
+
+func TestBotOptions_RequestTimeout(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	opts := NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetRequestTimeout(50 * time.Millisecond)
+
+	start := time.Now()
+	err := NewBot("tmp", opts).SendText("hi")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Actual error: %v, want: %v", err, context.DeadlineExceeded)
+	}
+	if !IsRetryable(err) {
+		t.Errorf("Expected request timeouts to be retryable: %v", err)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Actual duration: %s, want: < 1s", d)
+	}
+}
@@ -0,0 +1,600 @@
+// Package mdparse 解析 CommonMark（以及 GFM 删除线）的常用子集，供 Markdown 转换为富文本、卡片使用
+//
+// 不支持的语法（例如 HTML、引用式链接）按普通文本保留
+package mdparse
+
+import (
+	"strconv"
+	"strings"
+)
+
+type BlockKind int
+
+const (
+	BlockParagraph BlockKind = iota
+	BlockHeading
+	BlockCode
+	BlockList
+	BlockQuote
+	BlockThematicBreak
+	// BlockTable GFM 表格
+	BlockTable
+)
+
+// Align 表格列的对齐方式
+type Align int
+
+const (
+	AlignNone Align = iota
+	AlignLeft
+	AlignCenter
+	AlignRight
+)
+
+// Block 块级元素
+type Block struct {
+	Kind BlockKind
+
+	// BlockParagraph、BlockHeading 的内容
+	Inlines []Inline
+	// BlockHeading 的级别 1-6
+	Level int
+
+	// BlockCode 的语言（info string 的第一个单词）与代码
+	Language string
+	Code     string
+
+	// BlockList 是否有序、有序列表的起始序号，以及各列表项的内容
+	Ordered bool
+	Start   int
+	Items   [][]Block
+
+	// BlockQuote 的内容
+	Children []Block
+
+	// BlockTable 各列的对齐方式、表头以及各行的单元格，每行的单元格数量与列数一致
+	Aligns []Align
+	Header [][]Inline
+	Rows   [][][]Inline
+}
+
+// Parse 解析 Markdown 文档
+func Parse(src string) []Block {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\r", "\n")
+
+	lines := strings.Split(src, "\n")
+	for i := range lines {
+		lines[i] = expandTabs(lines[i])
+	}
+	return parseBlocks(lines)
+}
+
+func parseBlocks(lines []string) []Block {
+	var (
+		blocks []Block
+		para   []string
+	)
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		blocks = append(blocks, Block{Kind: BlockParagraph, Inlines: parseInlines(strings.Join(para, "\n"))})
+		para = nil
+	}
+
+	for i := 0; i < len(lines); {
+		line := lines[i]
+
+		switch {
+		case isBlank(line):
+			flush()
+			i++
+			continue
+		case len(para) != 0 && setextLevel(line) != 0:
+			blocks = append(blocks, Block{Kind: BlockHeading, Level: setextLevel(line), Inlines: parseInlines(strings.Join(para, "\n"))})
+			para = nil
+			i++
+			continue
+		case len(para) == 0 && indent(line) >= 4:
+			var block Block
+			block, i = parseIndentedCode(lines, i)
+			blocks = append(blocks, block)
+			continue
+		}
+
+		if fence, ok := parseFence(line); ok {
+			flush()
+			var block Block
+			block, i = parseFencedCode(lines, i, fence)
+			blocks = append(blocks, block)
+			continue
+		}
+		if isThematicBreak(line) {
+			flush()
+			blocks = append(blocks, Block{Kind: BlockThematicBreak})
+			i++
+			continue
+		}
+		if level, text, ok := parseATXHeading(line); ok {
+			flush()
+			blocks = append(blocks, Block{Kind: BlockHeading, Level: level, Inlines: parseInlines(text)})
+			i++
+			continue
+		}
+		if i+1 < len(lines) {
+			if aligns, ok := parseTableDelimiter(lines[i+1]); ok && len(splitTableRow(line)) == len(aligns) {
+				flush()
+				var block Block
+				block, i = parseTable(lines, i, aligns)
+				blocks = append(blocks, block)
+				continue
+			}
+		}
+		if _, ok := quoteContent(line); ok {
+			flush()
+			var block Block
+			block, i = parseQuote(lines, i)
+			blocks = append(blocks, block)
+			continue
+		}
+		if m, ok := parseListMarker(line); ok && (len(para) == 0 || m.canInterruptParagraph()) {
+			flush()
+			var block Block
+			block, i = parseList(lines, i)
+			blocks = append(blocks, block)
+			continue
+		}
+
+		para = append(para, strings.TrimLeft(line, " "))
+		i++
+	}
+	flush()
+
+	return blocks
+}
+
+// startsBlock line 是否开始一个新的块（而不是段落的延续）
+func startsBlock(line string) bool {
+	if isBlank(line) || isThematicBreak(line) {
+		return true
+	}
+	if _, ok := parseFence(line); ok {
+		return true
+	}
+	if _, _, ok := parseATXHeading(line); ok {
+		return true
+	}
+	if _, ok := quoteContent(line); ok {
+		return true
+	}
+	if m, ok := parseListMarker(line); ok && m.canInterruptParagraph() {
+		return true
+	}
+	return false
+}
+
+// --------------------------------------------------------------------------------
+
+func parseIndentedCode(lines []string, i int) (Block, int) {
+	var code []string
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		switch {
+		case isBlank(line):
+			code = append(code, "")
+		case indent(line) >= 4:
+			code = append(code, line[4:])
+		default:
+			return Block{Kind: BlockCode, Code: joinTrimBlank(code)}, i
+		}
+	}
+	return Block{Kind: BlockCode, Code: joinTrimBlank(code)}, i
+}
+
+func joinTrimBlank(lines []string) string {
+	for len(lines) != 0 && isBlank(lines[len(lines)-1]) {
+		lines = lines[:len(lines)-1]
+	}
+	return strings.Join(lines, "\n")
+}
+
+type fence struct {
+	indent   int
+	char     byte
+	length   int
+	language string
+}
+
+func parseFence(line string) (fence, bool) {
+	ind := indent(line)
+	if ind > 3 {
+		return fence{}, false
+	}
+	s := line[ind:]
+	if s == "" || (s[0] != '`' && s[0] != '~') {
+		return fence{}, false
+	}
+
+	n := countPrefix(s, s[0])
+	if n < 3 {
+		return fence{}, false
+	}
+	info := strings.TrimSpace(s[n:])
+	if s[0] == '`' && strings.Contains(info, "`") {
+		return fence{}, false
+	}
+
+	f := fence{indent: ind, char: s[0], length: n}
+	if fields := strings.Fields(info); len(fields) != 0 {
+		f.language = unescapeBackslash(fields[0])
+	}
+	return f, true
+}
+
+func parseFencedCode(lines []string, i int, f fence) (Block, int) {
+	var code []string
+	for i++; i < len(lines); i++ {
+		line := lines[i]
+		if ind := indent(line); ind <= 3 {
+			s := line[ind:]
+			if n := countPrefix(s, f.char); n >= f.length && isBlank(s[n:]) {
+				i++
+				break
+			}
+		}
+		code = append(code, line[min(indent(line), f.indent):])
+	}
+	return Block{Kind: BlockCode, Language: f.language, Code: strings.Join(code, "\n")}, i
+}
+
+func isThematicBreak(line string) bool {
+	ind := indent(line)
+	if ind > 3 {
+		return false
+	}
+	s := line[ind:]
+	if s == "" || (s[0] != '-' && s[0] != '*' && s[0] != '_') {
+		return false
+	}
+
+	n := 0
+	for j := 0; j < len(s); j++ {
+		switch s[j] {
+		case s[0]:
+			n++
+		case ' ':
+		default:
+			return false
+		}
+	}
+	return n >= 3
+}
+
+func setextLevel(line string) int {
+	ind := indent(line)
+	if ind > 3 {
+		return 0
+	}
+	s := strings.TrimRight(line[ind:], " ")
+	if s == "" {
+		return 0
+	}
+	if n := countPrefix(s, s[0]); n != len(s) {
+		return 0
+	}
+	switch s[0] {
+	case '=':
+		return 1
+	case '-':
+		return 2
+	default:
+		return 0
+	}
+}
+
+func parseATXHeading(line string) (level int, text string, ok bool) {
+	ind := indent(line)
+	if ind > 3 {
+		return 0, "", false
+	}
+	s := line[ind:]
+	level = countPrefix(s, '#')
+	if level == 0 || level > 6 || (len(s) > level && s[level] != ' ') {
+		return 0, "", false
+	}
+
+	text = strings.TrimSpace(s[level:])
+	// 去掉可选的结尾 #
+	if trimmed := strings.TrimRight(text, "#"); trimmed == "" {
+		text = ""
+	} else if len(trimmed) != len(text) && strings.HasSuffix(trimmed, " ") {
+		text = strings.TrimRight(trimmed, " ")
+	}
+	return level, text, true
+}
+
+// --------------------------------------------------------------------------------
+
+func quoteContent(line string) (string, bool) {
+	ind := indent(line)
+	if ind > 3 || len(line) == ind || line[ind] != '>' {
+		return "", false
+	}
+	s := line[ind+1:]
+	if strings.HasPrefix(s, " ") {
+		s = s[1:]
+	}
+	return s, true
+}
+
+func parseQuote(lines []string, i int) (Block, int) {
+	var (
+		content []string
+		lazy    bool // 上一行是否为可延续的段落
+	)
+	for ; i < len(lines); i++ {
+		line := lines[i]
+		if s, ok := quoteContent(line); ok {
+			content = append(content, s)
+			lazy = !isBlank(s)
+			continue
+		}
+		if lazy && !startsBlock(line) && indent(line) < 4 {
+			content = append(content, line)
+			continue
+		}
+		break
+	}
+	return Block{Kind: BlockQuote, Children: parseBlocks(content)}, i
+}
+
+// --------------------------------------------------------------------------------
+
+// parseTableDelimiter 解析表格的分隔行，例如 | --- | :-: | --: |
+func parseTableDelimiter(line string) ([]Align, bool) {
+	if indent(line) > 3 || !strings.ContainsAny(line, "|-") {
+		return nil, false
+	}
+	cells := splitTableRow(line)
+	// 只有一列时必须包含 |，避免与 setext 标题混淆
+	if len(cells) == 0 || (len(cells) == 1 && !strings.Contains(line, "|")) {
+		return nil, false
+	}
+
+	aligns := make([]Align, len(cells))
+	for i, cell := range cells {
+		cell = strings.TrimSpace(cell)
+		left, right := strings.HasPrefix(cell, ":"), strings.HasSuffix(cell, ":")
+		dashes := strings.TrimSuffix(strings.TrimPrefix(cell, ":"), ":")
+		if dashes == "" || strings.Trim(dashes, "-") != "" {
+			return nil, false
+		}
+		switch {
+		case left && right:
+			aligns[i] = AlignCenter
+		case left:
+			aligns[i] = AlignLeft
+		case right:
+			aligns[i] = AlignRight
+		}
+	}
+	return aligns, true
+}
+
+// splitTableRow 按未转义的 | 拆分表格的一行，忽略首尾的 |
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	if strings.HasPrefix(line, "|") {
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, `\|`) {
+		line = line[:len(line)-1]
+	}
+	if strings.TrimSpace(line) == "" {
+		return nil
+	}
+
+	var (
+		cells []string
+		cell  strings.Builder
+	)
+	for j := 0; j < len(line); j++ {
+		switch {
+		case line[j] == '\\' && j+1 < len(line) && line[j+1] == '|':
+			cell.WriteByte('|')
+			j++
+		case line[j] == '|':
+			cells = append(cells, cell.String())
+			cell.Reset()
+		default:
+			cell.WriteByte(line[j])
+		}
+	}
+	return append(cells, cell.String())
+}
+
+func parseTable(lines []string, i int, aligns []Align) (Block, int) {
+	row := func(line string) [][]Inline {
+		cells := splitTableRow(line)
+		ret := make([][]Inline, len(aligns))
+		for j := range ret {
+			if j < len(cells) {
+				ret[j] = parseInlines(cells[j])
+			}
+		}
+		return ret
+	}
+
+	block := Block{Kind: BlockTable, Aligns: aligns, Header: row(lines[i])}
+	for i += 2; i < len(lines) && !startsBlock(lines[i]); i++ {
+		block.Rows = append(block.Rows, row(lines[i]))
+	}
+	return block, i
+}
+
+// --------------------------------------------------------------------------------
+
+type listMarker struct {
+	ordered bool
+	// 无序列表为 - + *；有序列表为 . )
+	char  byte
+	start int
+	// 列表项内容的缩进
+	contentIndent int
+	// 列表项第一行的内容
+	rest string
+}
+
+func (m listMarker) canInterruptParagraph() bool {
+	return !isBlank(m.rest) && (!m.ordered || m.start == 1)
+}
+
+func (m listMarker) sameList(other listMarker) bool {
+	return m.ordered == other.ordered && m.char == other.char
+}
+
+func parseListMarker(line string) (listMarker, bool) {
+	ind := indent(line)
+	if ind > 3 || len(line) == ind {
+		return listMarker{}, false
+	}
+	s := line[ind:]
+
+	var m listMarker
+	markerLen := 0
+	switch s[0] {
+	case '-', '+', '*':
+		m.char, markerLen = s[0], 1
+	default:
+		digits := 0
+		for digits < len(s) && digits < 10 && s[digits] >= '0' && s[digits] <= '9' {
+			digits++
+		}
+		if digits == 0 || digits > 9 || digits == len(s) || (s[digits] != '.' && s[digits] != ')') {
+			return listMarker{}, false
+		}
+		m.ordered, m.char, markerLen = true, s[digits], digits+1
+		m.start, _ = strconv.Atoi(s[:digits])
+	}
+
+	after := s[markerLen:]
+	switch {
+	case isBlank(after):
+		m.contentIndent = ind + markerLen + 1
+		return m, true
+	case after[0] != ' ':
+		return listMarker{}, false
+	}
+
+	spaces := countPrefix(after, ' ')
+	if spaces > 4 {
+		// 内容为缩进代码块
+		spaces = 1
+	}
+	m.contentIndent = ind + markerLen + spaces
+	m.rest = line[m.contentIndent:]
+	return m, true
+}
+
+func parseList(lines []string, i int) (Block, int) {
+	first, _ := parseListMarker(lines[i])
+	block := Block{Kind: BlockList, Ordered: first.ordered, Start: first.start}
+
+	for i < len(lines) {
+		m, ok := parseListMarker(lines[i])
+		if !ok || !m.sameList(first) || isThematicBreak(lines[i]) {
+			break
+		}
+
+		item := []string{m.rest}
+		for i++; i < len(lines); {
+			line := lines[i]
+			if isBlank(line) {
+				// 空行之后仍有缩进足够的内容时，属于同一个列表项
+				j := nextNonBlank(lines, i)
+				if j < len(lines) && indent(lines[j]) >= m.contentIndent {
+					for ; i < j; i++ {
+						item = append(item, "")
+					}
+					continue
+				}
+				break
+			}
+			if indent(line) >= m.contentIndent {
+				item = append(item, line[m.contentIndent:])
+				i++
+				continue
+			}
+			// 段落的延续行；不缩进的列表项总是开始新的列表项或列表
+			if _, isItem := parseListMarker(line); !isItem && !isBlank(item[len(item)-1]) && !startsBlock(line) {
+				item = append(item, strings.TrimLeft(line, " "))
+				i++
+				continue
+			}
+			break
+		}
+		block.Items = append(block.Items, parseBlocks(item))
+
+		// 列表项之间的空行
+		if j := nextNonBlank(lines, i); j != i && j < len(lines) {
+			if next, ok := parseListMarker(lines[j]); ok && next.sameList(first) && !isThematicBreak(lines[j]) {
+				i = j
+			}
+		}
+	}
+	return block, i
+}
+
+// --------------------------------------------------------------------------------
+
+func isBlank(s string) bool {
+	return strings.TrimLeft(s, " \t") == ""
+}
+
+func indent(s string) int {
+	return countPrefix(s, ' ')
+}
+
+func countPrefix(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
+
+func nextNonBlank(lines []string, i int) int {
+	for i < len(lines) && isBlank(lines[i]) {
+		i++
+	}
+	return i
+}
+
+// expandTabs 将行首空白中的 tab 展开为空格（tab stop 为 4）
+func expandTabs(line string) string {
+	if !strings.Contains(line, "\t") {
+		return line
+	}
+
+	var (
+		sb    strings.Builder
+		col   int
+		start = true
+	)
+	for j := 0; j < len(line); j++ {
+		c := line[j]
+		if start && c == '\t' {
+			n := 4 - col%4
+			sb.WriteString(strings.Repeat(" ", n))
+			col += n
+			continue
+		}
+		if c != ' ' {
+			start = false
+		}
+		sb.WriteByte(c)
+		col++
+	}
+	return sb.String()
+}
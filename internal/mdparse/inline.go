@@ -0,0 +1,448 @@
+package mdparse
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type InlineKind int
+
+const (
+	InlineText InlineKind = iota
+	InlineCode
+	// InlineImage Text 为替代文本，Src 为图片地址
+	InlineImage
+	InlineSoftBreak
+	InlineHardBreak
+)
+
+// Style 行内样式，可以组合
+type Style uint8
+
+const (
+	StyleBold Style = 1 << iota
+	StyleItalic
+	StyleStrikethrough
+)
+
+// Inline 行内元素
+type Inline struct {
+	Kind  InlineKind
+	Text  string
+	Style Style
+	// 所在链接的地址，不在链接中时为空
+	Link string
+	// InlineImage 的图片地址
+	Src string
+}
+
+// PlainText 行内元素的纯文本内容，换行以空格代替
+func PlainText(inlines []Inline) string {
+	var sb strings.Builder
+	for _, inl := range inlines {
+		switch inl.Kind {
+		case InlineSoftBreak, InlineHardBreak:
+			sb.WriteString(" ")
+		default:
+			sb.WriteString(inl.Text)
+		}
+	}
+	return sb.String()
+}
+
+// --------------------------------------------------------------------------------
+
+// node 解析过程中的行内节点：普通内容或强调分隔符（* _ ~）
+type node struct {
+	inlines []Inline
+
+	delim             byte
+	count, origCount  int
+	canOpen, canClose bool
+	style             Style
+}
+
+type inlineParser struct {
+	src   string
+	nodes []node
+	text  strings.Builder
+}
+
+var (
+	_reEntity   = regexp.MustCompile(`^&(?:#[0-9]{1,7}|#[xX][0-9a-fA-F]{1,6}|[A-Za-z][A-Za-z0-9]{1,31});`)
+	_reAutolink = regexp.MustCompile(`^<([A-Za-z][A-Za-z0-9+.-]{1,31}:[^<>\x00-\x20]*)>`)
+	_reEmail    = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*)>`)
+)
+
+func parseInlines(src string) []Inline {
+	p := &inlineParser{src: strings.TrimSpace(src)}
+	p.parse()
+	p.processEmphasis()
+	return p.flatten()
+}
+
+func (p *inlineParser) parse() {
+	src := p.src
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch c {
+		case '\\':
+			if i+1 < len(src) && src[i+1] == '\n' {
+				p.push(Inline{Kind: InlineHardBreak})
+				i = skipLeadingSpaces(src, i+2)
+				continue
+			}
+			if i+1 < len(src) && isASCIIPunct(src[i+1]) {
+				p.text.WriteByte(src[i+1])
+				i += 2
+				continue
+			}
+			p.text.WriteByte(c)
+			i++
+		case '`':
+			n := countPrefix(src[i:], '`')
+			if code, end, ok := codeSpan(src, i, n); ok {
+				p.push(Inline{Kind: InlineCode, Text: code})
+				i = end
+				continue
+			}
+			p.text.WriteString(src[i : i+n])
+			i += n
+		case '!', '[':
+			start := i
+			if c == '!' {
+				if i+1 >= len(src) || src[i+1] != '[' {
+					p.text.WriteByte(c)
+					i++
+					continue
+				}
+				start++
+			}
+			label, dest, end, ok := parseLink(src, start)
+			if !ok {
+				p.text.WriteString(src[i : start+1])
+				i = start + 1
+				continue
+			}
+			inner := parseInlines(label)
+			if c == '!' {
+				p.push(Inline{Kind: InlineImage, Text: PlainText(inner), Src: dest})
+			} else {
+				for j := range inner {
+					if inner[j].Link == "" {
+						inner[j].Link = dest
+					}
+				}
+				p.push(inner...)
+			}
+			i = end
+		case '<':
+			if m := _reAutolink.FindStringSubmatch(src[i:]); m != nil {
+				p.push(Inline{Kind: InlineText, Text: m[1], Link: m[1]})
+				i += len(m[0])
+				continue
+			}
+			if m := _reEmail.FindStringSubmatch(src[i:]); m != nil {
+				p.push(Inline{Kind: InlineText, Text: m[1], Link: "mailto:" + m[1]})
+				i += len(m[0])
+				continue
+			}
+			p.text.WriteByte(c)
+			i++
+		case '&':
+			if m := _reEntity.FindString(src[i:]); m != "" {
+				p.text.WriteString(html.UnescapeString(m))
+				i += len(m)
+				continue
+			}
+			p.text.WriteByte(c)
+			i++
+		case '*', '_', '~':
+			n := countPrefix(src[i:], c)
+			p.pushDelim(src, i, n)
+			i += n
+		case '\n':
+			hard := strings.HasSuffix(p.text.String(), "  ")
+			p.trimTrailingSpaces()
+			if hard {
+				p.push(Inline{Kind: InlineHardBreak})
+			} else {
+				p.push(Inline{Kind: InlineSoftBreak})
+			}
+			i = skipLeadingSpaces(src, i+1)
+		default:
+			p.text.WriteByte(c)
+			i++
+		}
+	}
+	p.flushText()
+}
+
+func (p *inlineParser) flushText() {
+	if p.text.Len() == 0 {
+		return
+	}
+	p.nodes = append(p.nodes, node{inlines: []Inline{{Kind: InlineText, Text: p.text.String()}}})
+	p.text.Reset()
+}
+
+func (p *inlineParser) trimTrailingSpaces() {
+	s := strings.TrimRight(p.text.String(), " ")
+	p.text.Reset()
+	p.text.WriteString(s)
+}
+
+func (p *inlineParser) push(inlines ...Inline) {
+	p.flushText()
+	p.nodes = append(p.nodes, node{inlines: inlines})
+}
+
+func (p *inlineParser) pushDelim(src string, i, n int) {
+	p.flushText()
+
+	c := src[i]
+	if c == '~' && n > 2 {
+		p.nodes = append(p.nodes, node{inlines: []Inline{{Kind: InlineText, Text: src[i : i+n]}}})
+		return
+	}
+
+	prev, next := ' ', ' '
+	if i > 0 {
+		prev, _ = utf8.DecodeLastRuneInString(src[:i])
+	}
+	if i+n < len(src) {
+		next, _ = utf8.DecodeRuneInString(src[i+n:])
+	}
+
+	left := !unicode.IsSpace(next) && (!isPunct(next) || unicode.IsSpace(prev) || isPunct(prev))
+	right := !unicode.IsSpace(prev) && (!isPunct(prev) || unicode.IsSpace(next) || isPunct(next))
+
+	nd := node{delim: c, count: n, origCount: n, canOpen: left, canClose: right}
+	if c == '_' {
+		nd.canOpen = left && (!right || isPunct(prev))
+		nd.canClose = right && (!left || isPunct(next))
+	}
+	p.nodes = append(p.nodes, nd)
+}
+
+// processEmphasis 匹配强调分隔符，参考 https://spec.commonmark.org/0.31.2/#process-emphasis
+func (p *inlineParser) processEmphasis() {
+	for c := range p.nodes {
+		closer := &p.nodes[c]
+		if closer.delim == 0 || !closer.canClose {
+			continue
+		}
+
+		for closer.count > 0 {
+			o := p.findOpener(c)
+			if o < 0 {
+				break
+			}
+			opener := &p.nodes[o]
+
+			use, style := 1, StyleItalic
+			switch {
+			case closer.delim == '~':
+				use, style = opener.count, StyleStrikethrough
+			case opener.count >= 2 && closer.count >= 2:
+				use, style = 2, StyleBold
+			}
+
+			for k := o + 1; k < c; k++ {
+				p.nodes[k].style |= style
+				// 中间未匹配的分隔符不再参与匹配
+				p.nodes[k].canOpen, p.nodes[k].canClose = false, false
+			}
+			opener.count -= use
+			closer.count -= use
+		}
+	}
+}
+
+func (p *inlineParser) findOpener(c int) int {
+	closer := p.nodes[c]
+	for o := c - 1; o >= 0; o-- {
+		opener := p.nodes[o]
+		if opener.delim != closer.delim || !opener.canOpen || opener.count == 0 {
+			continue
+		}
+		if closer.delim == '~' {
+			if opener.count != closer.count {
+				continue
+			}
+			return o
+		}
+		// rule of 3
+		if (opener.canClose || closer.canOpen) &&
+			(opener.origCount+closer.origCount)%3 == 0 &&
+			!(opener.origCount%3 == 0 && closer.origCount%3 == 0) {
+			continue
+		}
+		return o
+	}
+	return -1
+}
+
+func (p *inlineParser) flatten() []Inline {
+	var ret []Inline
+	add := func(inl Inline) {
+		if n := len(ret); n != 0 && inl.Kind == InlineText && ret[n-1].Kind == InlineText &&
+			ret[n-1].Style == inl.Style && ret[n-1].Link == inl.Link {
+			ret[n-1].Text += inl.Text
+			return
+		}
+		ret = append(ret, inl)
+	}
+
+	for _, nd := range p.nodes {
+		if nd.delim != 0 {
+			if nd.count > 0 {
+				add(Inline{Kind: InlineText, Text: strings.Repeat(string(nd.delim), nd.count), Style: nd.style})
+			}
+			continue
+		}
+		for _, inl := range nd.inlines {
+			inl.Style |= nd.style
+			add(inl)
+		}
+	}
+	return ret
+}
+
+// --------------------------------------------------------------------------------
+
+// codeSpan 解析从 i 开始、由 n 个反引号包围的行内代码
+func codeSpan(src string, i, n int) (code string, end int, ok bool) {
+	for j := i + n; j < len(src); {
+		if src[j] != '`' {
+			j++
+			continue
+		}
+		m := countPrefix(src[j:], '`')
+		if m == n {
+			code = strings.ReplaceAll(src[i+n:j], "\n", " ")
+			if len(code) > 2 && code[0] == ' ' && code[len(code)-1] == ' ' && strings.Trim(code, " ") != "" {
+				code = code[1 : len(code)-1]
+			}
+			return code, j + m, true
+		}
+		j += m
+	}
+	return "", 0, false
+}
+
+// parseLink 解析从 i（[ 所在位置）开始的行内链接 [label](dest "title")
+func parseLink(src string, i int) (label, dest string, end int, ok bool) {
+	// 匹配的 ]
+	depth, j := 0, i
+	for ; j < len(src); j++ {
+		switch src[j] {
+		case '\\':
+			j++
+		case '`':
+			n := countPrefix(src[j:], '`')
+			if _, e, ok := codeSpan(src, j, n); ok {
+				j = e - 1
+			} else {
+				j += n - 1
+			}
+		case '[':
+			depth++
+		case ']':
+			depth--
+		}
+		if depth == 0 {
+			break
+		}
+	}
+	if j >= len(src) || j+1 >= len(src) || src[j+1] != '(' {
+		return "", "", 0, false
+	}
+	label = src[i+1 : j]
+
+	k := skipSpaces(src, j+2)
+	if k < len(src) && src[k] == '<' {
+		e := strings.IndexAny(src[k+1:], ">\n")
+		if e < 0 || src[k+1+e] != '>' {
+			return "", "", 0, false
+		}
+		dest = src[k+1 : k+1+e]
+		k += e + 2
+	} else {
+		start, parens := k, 0
+	loop:
+		for ; k < len(src); k++ {
+			switch c := src[k]; {
+			case c == '\\' && k+1 < len(src):
+				k++
+			case c == '(':
+				parens++
+			case c == ')':
+				if parens == 0 {
+					break loop
+				}
+				parens--
+			case c <= ' ':
+				break loop
+			}
+		}
+		dest = src[start:k]
+	}
+
+	k = skipSpaces(src, k)
+	if k < len(src) && (src[k] == '"' || src[k] == '\'' || src[k] == '(') {
+		closing := src[k]
+		if closing == '(' {
+			closing = ')'
+		}
+		e := strings.IndexByte(src[k+1:], closing)
+		if e < 0 {
+			return "", "", 0, false
+		}
+		k = skipSpaces(src, k+e+2)
+	}
+	if k >= len(src) || src[k] != ')' {
+		return "", "", 0, false
+	}
+
+	return label, html.UnescapeString(unescapeBackslash(dest)), k + 1, true
+}
+
+func skipSpaces(src string, i int) int {
+	for i < len(src) && (src[i] == ' ' || src[i] == '\n') {
+		i++
+	}
+	return i
+}
+
+func skipLeadingSpaces(src string, i int) int {
+	for i < len(src) && src[i] == ' ' {
+		i++
+	}
+	return i
+}
+
+func unescapeBackslash(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) && isASCIIPunct(s[i+1]) {
+			i++
+		}
+		sb.WriteByte(s[i])
+	}
+	return sb.String()
+}
+
+func isASCIIPunct(c byte) bool {
+	return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) >= 0
+}
+
+func isPunct(r rune) bool {
+	return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
@@ -0,0 +1,195 @@
+package mdparse
+
+import (
+	"reflect"
+	"testing"
+)
+
+func text(s string) Inline { return Inline{Kind: InlineText, Text: s} }
+
+func styled(s string, style Style) Inline { return Inline{Kind: InlineText, Text: s, Style: style} }
+
+func para(inlines ...Inline) Block { return Block{Kind: BlockParagraph, Inlines: inlines} }
+
+func TestParse_blocks(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []Block
+	}{
+		{
+			name: "paragraphs",
+			src:  "a\nb\n\n\nc",
+			want: []Block{para(text("a"), Inline{Kind: InlineSoftBreak}, text("b")), para(text("c"))},
+		},
+		{
+			name: "headings",
+			src:  "# h1 #\n###### h6\n####### no\nsetext\n---",
+			want: []Block{
+				{Kind: BlockHeading, Level: 1, Inlines: []Inline{text("h1")}},
+				{Kind: BlockHeading, Level: 6, Inlines: []Inline{text("h6")}},
+				{Kind: BlockHeading, Level: 2, Inlines: []Inline{text("####### no"), {Kind: InlineSoftBreak}, text("setext")}},
+			},
+		},
+		{
+			name: "fenced_code",
+			src:  "```go title\nfunc main() {\n\tprintln()\n}\n```\n~~~\nunclosed",
+			want: []Block{
+				{Kind: BlockCode, Language: "go", Code: "func main() {\n    println()\n}"},
+				{Kind: BlockCode, Code: "unclosed"},
+			},
+		},
+		{
+			name: "indented_code",
+			src:  "    a\n\n    b\n\nc",
+			want: []Block{{Kind: BlockCode, Code: "a\n\nb"}, para(text("c"))},
+		},
+		{
+			name: "thematic_break",
+			src:  "a\n\n* * *\n___",
+			want: []Block{para(text("a")), {Kind: BlockThematicBreak}, {Kind: BlockThematicBreak}},
+		},
+		{
+			name: "quote",
+			src:  "> a\nlazy\n>\n> - b",
+			want: []Block{{Kind: BlockQuote, Children: []Block{
+				para(text("a"), Inline{Kind: InlineSoftBreak}, text("lazy")),
+				{Kind: BlockList, Items: [][]Block{{para(text("b"))}}},
+			}}},
+		},
+		{
+			name: "lists",
+			src:  "- a\n  continued\n- b\n  - nested\n\n  more\n* other\n\n3. three\n4. four",
+			want: []Block{
+				{Kind: BlockList, Items: [][]Block{
+					{para(text("a"), Inline{Kind: InlineSoftBreak}, text("continued"))},
+					{para(text("b")), {Kind: BlockList, Items: [][]Block{{para(text("nested"))}}}, para(text("more"))},
+				}},
+				{Kind: BlockList, Items: [][]Block{{para(text("other"))}}},
+				{Kind: BlockList, Ordered: true, Start: 3, Items: [][]Block{{para(text("three"))}, {para(text("four"))}}},
+			},
+		},
+		{
+			name: "table",
+			src:  "| a | b \\| c | d | |\n|:--|:-:|--:|---|\n| 1 | **2** |\nx | y | z | w | extra\n\nafter",
+			want: []Block{
+				{
+					Kind:   BlockTable,
+					Aligns: []Align{AlignLeft, AlignCenter, AlignRight, AlignNone},
+					Header: [][]Inline{{text("a")}, {text("b | c")}, {text("d")}, nil},
+					Rows: [][][]Inline{
+						{{text("1")}, {styled("2", StyleBold)}, nil, nil},
+						{{text("x")}, {text("y")}, {text("z")}, {text("w")}},
+					},
+				},
+				para(text("after")),
+			},
+		},
+		{
+			name: "table_mismatch",
+			src:  "a | b\n--- | --- | ---\n\nsetext\n---",
+			want: []Block{
+				para(text("a | b"), Inline{Kind: InlineSoftBreak}, text("--- | --- | ---")),
+				{Kind: BlockHeading, Level: 2, Inlines: []Inline{text("setext")}},
+			},
+		},
+		{
+			name: "ordered_list_interrupt",
+			src:  "a\n2. no\n1. yes",
+			want: []Block{
+				para(text("a"), Inline{Kind: InlineSoftBreak}, text("2. no")),
+				{Kind: BlockList, Ordered: true, Start: 1, Items: [][]Block{{para(text("yes"))}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Parse(tt.src); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Actual:\n%+v\nwant:\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_inlines(t *testing.T) {
+	tests := []struct {
+		name string
+		src  string
+		want []Inline
+	}{
+		{
+			name: "emphasis",
+			src:  "**b** *i* _i_ ~~s~~ ***bi***",
+			want: []Inline{
+				styled("b", StyleBold), text(" "), styled("i", StyleItalic), text(" "), styled("i", StyleItalic), text(" "),
+				styled("s", StyleStrikethrough), text(" "), styled("bi", StyleBold|StyleItalic),
+			},
+		},
+		{
+			name: "emphasis_nested",
+			src:  "*a **b** c*",
+			want: []Inline{styled("a ", StyleItalic), styled("b", StyleBold|StyleItalic), styled(" c", StyleItalic)},
+		},
+		{
+			name: "emphasis_literal",
+			src:  "snake_case_name 2 * 3 **unclosed ~~~x~~~",
+			want: []Inline{text("snake_case_name 2 * 3 **unclosed ~~~x~~~")},
+		},
+		{
+			name: "escape_and_entity",
+			src:  `\*a\* &amp; &copy; &nope; \q`,
+			want: []Inline{text(`*a* & © &nope; \q`)},
+		},
+		{
+			name: "code",
+			src:  "`a` `` b`c `` `unclosed",
+			want: []Inline{
+				{Kind: InlineCode, Text: "a"}, text(" "), {Kind: InlineCode, Text: "b`c"}, text(" `unclosed"),
+			},
+		},
+		{
+			name: "links",
+			src:  `[a *b*](https://x.com/(1) "title") [c](<d e>) <https://f.com> <g@h.com> [no] [no](`,
+			want: []Inline{
+				{Kind: InlineText, Text: "a ", Link: "https://x.com/(1)"},
+				{Kind: InlineText, Text: "b", Link: "https://x.com/(1)", Style: StyleItalic},
+				text(" "),
+				{Kind: InlineText, Text: "c", Link: "d e"},
+				text(" "),
+				{Kind: InlineText, Text: "https://f.com", Link: "https://f.com"},
+				text(" "),
+				{Kind: InlineText, Text: "g@h.com", Link: "mailto:g@h.com"},
+				text(" [no] [no]("),
+			},
+		},
+		{
+			name: "image",
+			src:  "![alt *x*](img.png) [![i](a.png)](b)",
+			want: []Inline{
+				{Kind: InlineImage, Text: "alt x", Src: "img.png"},
+				text(" "),
+				{Kind: InlineImage, Text: "i", Src: "a.png", Link: "b"},
+			},
+		},
+		{
+			name: "breaks",
+			src:  "a  \nb\\\nc\n  d",
+			want: []Inline{
+				text("a"), {Kind: InlineHardBreak}, text("b"), {Kind: InlineHardBreak}, text("c"), {Kind: InlineSoftBreak}, text("d"),
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			blocks := Parse(tt.src)
+			if len(blocks) != 1 || blocks[0].Kind != BlockParagraph {
+				t.Fatalf("Actual blocks: %+v, want one paragraph", blocks)
+			}
+			if got := blocks[0].Inlines; !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Actual:\n%+v\nwant:\n%+v", got, tt.want)
+			}
+		})
+	}
+}
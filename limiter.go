@@ -0,0 +1,247 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	"github.com/electricbubble/feishu-bot-api/v2/clock"
+)
+
+// Limiter 限流器
+//
+// 飞书对每个自定义机器人的频率限制为 100 次/分钟，5 次/秒
+// https://open.feishu.cn/document/client-docs/bot-v3/add-custom-bot
+type Limiter interface {
+	// Wait 阻塞直到获取一个令牌，ctx 被取消时返回 ctx.Err()
+	Wait(ctx context.Context) error
+
+	// Reserve 尝试立即获取一个令牌（不阻塞）
+	//
+	// 获取失败时 ok 为 false，retryAfter 为下一次尝试前建议等待的时间
+	Reserve() (retryAfter time.Duration, ok bool)
+
+	// Tokens 当前可以立即获取的令牌数
+	Tokens() float64
+}
+
+var _ Limiter = (*windowLimiter)(nil)
+
+// windowLimiter 按自然秒、自然分钟计数的限流器
+type windowLimiter struct {
+	second, minute *rate.Limiter
+	clock          clock.Clock
+}
+
+// NewLimiter 默认的限流器，按自然秒、自然分钟限制令牌数
+func NewLimiter(perSecond, perMinute int) Limiter {
+	return NewLimiterWithClock(perSecond, perMinute, clock.System())
+}
+
+// NewLimiterWithClock 同 NewLimiter，使用 c 作为时间来源
+func NewLimiterWithClock(perSecond, perMinute int, c clock.Clock) Limiter {
+	return &windowLimiter{
+		second: rate.NewLimiter(rate.Limit(perSecond), perSecond),
+		minute: rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute),
+		clock:  c,
+	}
+}
+
+func (l *windowLimiter) Wait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+REDO:
+
+	now := l.clock.Now()
+
+	{
+		ts := time.Unix(now.Unix(), 0)
+
+		if l.second.TokensAt(ts) <= 0 {
+			if err := sleepContext(ctx, l.clock, ts.Add(time.Second).Sub(now)); err != nil {
+				return err
+			}
+			goto REDO
+		}
+
+		rs := l.second.ReserveN(ts, 1)
+		if !rs.OK() {
+			return errors.New("limiter(second): not allowed to act")
+		}
+
+		switch d := rs.DelayFrom(ts); d {
+		case rate.InfDuration:
+			return errors.New("limiter(second): cannot grant the token")
+		case 0:
+		default:
+			if err := sleepContext(ctx, l.clock, d); err != nil {
+				rs.CancelAt(ts)
+				return err
+			}
+		}
+	}
+
+	{
+		tm := time.Unix(now.Unix()-int64(now.Second()), 0)
+
+		if l.minute.TokensAt(tm) <= 0 {
+			if err := sleepContext(ctx, l.clock, tm.Add(time.Minute).Sub(now)); err != nil {
+				return err
+			}
+			goto REDO
+		}
+
+		rm := l.minute.ReserveN(tm, 1)
+		if !rm.OK() {
+			return errors.New("limiter(minute): not allowed to act")
+		}
+		switch d := rm.DelayFrom(tm); d {
+		case rate.InfDuration:
+			return errors.New("limiter(minute): cannot grant the token")
+		case 0:
+		default:
+			if err := sleepContext(ctx, l.clock, d); err != nil {
+				rm.CancelAt(tm)
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (l *windowLimiter) Reserve() (time.Duration, bool) {
+	now := l.clock.Now()
+	ts := time.Unix(now.Unix(), 0)
+	tm := time.Unix(now.Unix()-int64(now.Second()), 0)
+
+	if l.second.TokensAt(ts) <= 0 {
+		return ts.Add(time.Second).Sub(now), false
+	}
+	if l.minute.TokensAt(tm) <= 0 {
+		return tm.Add(time.Minute).Sub(now), false
+	}
+
+	rs := l.second.ReserveN(ts, 1)
+	if d := rs.DelayFrom(ts); !rs.OK() || d > 0 {
+		rs.CancelAt(ts)
+		return max(d, 0), false
+	}
+
+	rm := l.minute.ReserveN(tm, 1)
+	if d := rm.DelayFrom(tm); !rm.OK() || d > 0 {
+		rm.CancelAt(tm)
+		rs.CancelAt(ts)
+		return max(d, 0), false
+	}
+
+	return 0, true
+}
+
+func (l *windowLimiter) Tokens() float64 {
+	now := l.clock.Now()
+	ts := time.Unix(now.Unix(), 0)
+	tm := time.Unix(now.Unix()-int64(now.Second()), 0)
+	return min(l.second.TokensAt(ts), l.minute.TokensAt(tm))
+}
+
+// --------------------------------------------------------------------------------
+
+// DefaultLimiterRegistry 默认的限流器注册表，未指定 BotOptions.LimiterRegistry 时使用
+//
+// 其中的限流器在进程的整个生命周期内保留，不会自动淘汰：webhook 会轮换或按租户动态创建的长期运行服务，
+// 应在不再使用某个 webhook 时调用 Delete，或者为这类 Bot 指定独立的 LimiterRegistry
+var DefaultLimiterRegistry = NewLimiterRegistry()
+
+// LimiterRegistry 按 webhook access token 共享限流器
+//
+// 同一进程内指向同一 webhook、且限流配置（每秒、每分钟次数以及时钟）相同的多个 Bot 共享同一份令牌；
+// 配置不同的 Bot 各自使用独立的限流器，不会使用其他 Bot 的时钟
+//
+// 限流器创建后一直保留到调用 Delete 为止（仍在使用的 Bot 不受影响，但之后创建的 Bot 不再与其共享令牌）
+type LimiterRegistry struct {
+	mu       sync.Mutex
+	limiters map[string]Limiter
+	shared   map[limiterKey]Limiter
+}
+
+// limiterKey 共享限流器的配置
+type limiterKey struct {
+	token                string
+	perSecond, perMinute int
+	clock                clock.Clock
+}
+
+func NewLimiterRegistry() *LimiterRegistry {
+	return &LimiterRegistry{
+		limiters: make(map[string]Limiter),
+		shared:   make(map[limiterKey]Limiter),
+	}
+}
+
+// Get 返回 token 对应的限流器，不存在时使用 newLimiter 创建
+//
+// 通过 Get、Set 指定的限流器由该 token 的所有 Bot 共享，优先于按配置创建的限流器
+func (r *LimiterRegistry) Get(token string, newLimiter func() Limiter) Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.limiters[token]; ok {
+		return l
+	}
+	l := newLimiter()
+	r.limiters[token] = l
+	return l
+}
+
+// Set 为 token 指定限流器，覆盖已存在的限流器
+func (r *LimiterRegistry) Set(token string, l Limiter) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limiters[token] = l
+}
+
+// Delete 删除 token 对应的限流器（包括 Get、Set 指定的以及按配置创建的），用于释放不再使用的 webhook 占用的内存
+func (r *LimiterRegistry) Delete(token string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.limiters, token)
+	for key := range r.shared {
+		if key.token == token {
+			delete(r.shared, key)
+		}
+	}
+}
+
+// limiter 返回与 token 以及限流配置对应的限流器，不存在时创建
+//
+// 时钟无法比较（不能作为 map 的 key）时不共享，每次创建新的限流器
+func (r *LimiterRegistry) limiter(token string, perSecond, perMinute int, c clock.Clock) Limiter {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if l, ok := r.limiters[token]; ok {
+		return l
+	}
+	if !comparableClock(c) {
+		return NewLimiterWithClock(perSecond, perMinute, c)
+	}
+
+	key := limiterKey{token: token, perSecond: perSecond, perMinute: perMinute, clock: c}
+	if l, ok := r.shared[key]; ok {
+		return l
+	}
+	l := NewLimiterWithClock(perSecond, perMinute, c)
+	r.shared[key] = l
+	return l
+}
+
+func comparableClock(c clock.Clock) bool {
+	return c == nil || reflect.TypeOf(c).Comparable()
+}
@@ -0,0 +1,222 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/electricbubble/feishu-bot-api/v2/clock"
+)
+
+// LimiterState 固定窗口限流的计数状态
+type LimiterState struct {
+	// Second 当前秒窗口的开始时间（Unix 时间戳，单位秒）
+	Second int64 `json:"second"`
+	// SecondCount 当前秒窗口内已发放的令牌数
+	SecondCount int `json:"second_count"`
+
+	// Minute 当前分钟窗口的开始时间（Unix 时间戳，单位秒）
+	Minute int64 `json:"minute"`
+	// MinuteCount 当前分钟窗口内已发放的令牌数
+	MinuteCount int `json:"minute_count"`
+}
+
+// LimiterStore 限流状态的存储
+//
+// 实现需要保证 Update 对同一个 key 的读取、修改、写回是原子的（包括跨进程），
+// 例如基于文件锁（FileLimiterStore），或者基于 Redis 的 WATCH/MULTI、Lua 脚本
+type LimiterStore interface {
+	// Update 读取 key 对应的状态（不存在时为零值），调用 fn 修改后写回；fn 返回 error 时不写回
+	Update(ctx context.Context, key string, fn func(state *LimiterState) error) error
+}
+
+var _ Limiter = (*storeLimiter)(nil)
+
+// storeLimiter 基于 LimiterStore 的固定窗口限流器，可以在多个进程、多个副本之间共享令牌
+type storeLimiter struct {
+	store                LimiterStore
+	key                  string
+	perSecond, perMinute int
+	clock                clock.Clock
+}
+
+// NewStoreLimiter 基于 LimiterStore 的限流器，按自然秒、自然分钟限制令牌数
+//
+// key 用于区分不同的 webhook，通常使用 webhook access token
+func NewStoreLimiter(store LimiterStore, key string, perSecond, perMinute int) Limiter {
+	return NewStoreLimiterWithClock(store, key, perSecond, perMinute, clock.System())
+}
+
+// NewStoreLimiterWithClock 同 NewStoreLimiter，使用 c 作为时间来源
+func NewStoreLimiterWithClock(store LimiterStore, key string, perSecond, perMinute int, c clock.Clock) Limiter {
+	return &storeLimiter{
+		store:     store,
+		key:       key,
+		perSecond: perSecond,
+		perMinute: perMinute,
+		clock:     c,
+	}
+}
+
+func (l *storeLimiter) Wait(ctx context.Context) error {
+	for {
+		retryAfter, ok, err := l.reserve(ctx)
+		if err != nil {
+			return err
+		}
+		if ok {
+			return nil
+		}
+
+		if err := sleepContext(ctx, l.clock, retryAfter); err != nil {
+			return err
+		}
+	}
+}
+
+// Reserve 尝试立即获取一个令牌
+//
+// 读写 LimiterStore 失败时视为获取失败，retryAfter 为 1 秒
+func (l *storeLimiter) Reserve() (time.Duration, bool) {
+	retryAfter, ok, err := l.reserve(context.Background())
+	if err != nil {
+		return time.Second, false
+	}
+	return retryAfter, ok
+}
+
+// Tokens 当前可以立即获取的令牌数，读取 LimiterStore 失败时返回 0
+func (l *storeLimiter) Tokens() float64 {
+	var tokens int
+	err := l.store.Update(context.Background(), l.key, func(state *LimiterState) error {
+		l.rollWindows(state, l.clock.Now())
+		tokens = min(l.perSecond-state.SecondCount, l.perMinute-state.MinuteCount)
+		return nil
+	})
+	if err != nil {
+		return 0
+	}
+	return float64(max(tokens, 0))
+}
+
+func (l *storeLimiter) reserve(ctx context.Context) (retryAfter time.Duration, ok bool, err error) {
+	now := l.clock.Now()
+
+	err = l.store.Update(ctx, l.key, func(state *LimiterState) error {
+		l.rollWindows(state, now)
+
+		switch {
+		case state.MinuteCount >= l.perMinute:
+			retryAfter = time.Unix(state.Minute, 0).Add(time.Minute).Sub(now)
+		case state.SecondCount >= l.perSecond:
+			retryAfter = time.Unix(state.Second, 0).Add(time.Second).Sub(now)
+		default:
+			state.SecondCount++
+			state.MinuteCount++
+			ok = true
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, false, fmt.Errorf("limiter(store): %w", err)
+	}
+	return retryAfter, ok, nil
+}
+
+// rollWindows 进入新的窗口时重置计数
+func (l *storeLimiter) rollWindows(state *LimiterState, now time.Time) {
+	if sec := now.Unix(); state.Second != sec {
+		state.Second = sec
+		state.SecondCount = 0
+	}
+	if m := now.Unix() - int64(now.Second()); state.Minute != m {
+		state.Minute = m
+		state.MinuteCount = 0
+	}
+}
+
+// --------------------------------------------------------------------------------
+
+var _ LimiterStore = (*FileLimiterStore)(nil)
+
+// FileLimiterStore 基于文件的限流状态存储
+//
+// 每个 key 对应目录中的一个文件，通过文件锁（flock）在同一台机器的多个进程之间同步，进程重启后状态依然有效
+type FileLimiterStore struct {
+	dir string
+
+	// lockRetryInterval 获取文件锁失败时的重试间隔
+	lockRetryInterval time.Duration
+}
+
+func NewFileLimiterStore(dir string) (*FileLimiterStore, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("file limiter store: mkdir: %w", err)
+	}
+	return &FileLimiterStore{dir: dir, lockRetryInterval: 5 * time.Millisecond}, nil
+}
+
+func (s *FileLimiterStore) Update(ctx context.Context, key string, fn func(state *LimiterState) error) (err error) {
+	f, err := os.OpenFile(s.path(key), os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return fmt.Errorf("open: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			return fmt.Errorf("lock: %w", err)
+		}
+		if locked {
+			break
+		}
+		if err := sleepContext(ctx, clock.System(), s.lockRetryInterval); err != nil {
+			return err
+		}
+	}
+	defer func() {
+		if e := unlockFile(f); e != nil && err == nil {
+			err = fmt.Errorf("unlock: %w", e)
+		}
+	}()
+
+	raw, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+
+	var state LimiterState
+	if len(raw) > 0 {
+		// 内容损坏时从零值开始计数
+		_ = json.Unmarshal(raw, &state)
+	}
+
+	if err := fn(&state); err != nil {
+		return err
+	}
+
+	raw, err = json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+	if err := f.Truncate(0); err != nil {
+		return fmt.Errorf("truncate: %w", err)
+	}
+	if _, err := f.WriteAt(raw, 0); err != nil {
+		return fmt.Errorf("write: %w", err)
+	}
+	return nil
+}
+
+// path key 可能是 webhook access token，文件名中只使用其摘要
+func (s *FileLimiterStore) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, "limiter-"+hex.EncodeToString(sum[:8])+".json")
+}
@@ -0,0 +1,144 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/electricbubble/feishu-bot-api/v2/clock/clocktest"
+)
+
+// memoryLimiterStore 模拟 Redis 等外部存储
+type memoryLimiterStore struct {
+	mu     sync.Mutex
+	states map[string]LimiterState
+	err    error
+}
+
+func (s *memoryLimiterStore) Update(ctx context.Context, key string, fn func(state *LimiterState) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.err != nil {
+		return s.err
+	}
+	if s.states == nil {
+		s.states = make(map[string]LimiterState)
+	}
+
+	state := s.states[key]
+	if err := fn(&state); err != nil {
+		return err
+	}
+	s.states[key] = state
+	return nil
+}
+
+func Test_storeLimiter(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	t.Run("per_second", func(t *testing.T) {
+		clk := clocktest.NewFakeClock(start).AutoAdvance(true)
+
+		store := &memoryLimiterStore{}
+		l1 := NewStoreLimiterWithClock(store, "token", 2, 100, clk)
+		l2 := NewStoreLimiterWithClock(store, "token", 2, 100, clk)
+
+		if _, ok := l1.Reserve(); !ok {
+			t.Fatal("l1.Reserve failed")
+		}
+		if _, ok := l2.Reserve(); !ok {
+			t.Fatal("l2.Reserve failed")
+		}
+		if n := l1.Tokens(); n != 0 {
+			t.Errorf("Actual tokens: %v, want: 0", n)
+		}
+		retryAfter, ok := l2.Reserve()
+		if ok || retryAfter != time.Second {
+			t.Errorf("Actual: %s, %v", retryAfter, ok)
+		}
+
+		if _, ok := NewStoreLimiterWithClock(store, "other", 2, 100, clk).Reserve(); !ok {
+			t.Error("Expected other keys not to share the state")
+		}
+
+		requireNoError(t, l1.Wait(context.Background()))
+		if d := clk.Now().Sub(start); d != time.Second {
+			t.Errorf("Actual wait: %s, want: %s", d, time.Second)
+		}
+	})
+
+	t.Run("per_minute", func(t *testing.T) {
+		clk := clocktest.NewFakeClock(start)
+
+		store := &memoryLimiterStore{}
+		l := NewStoreLimiterWithClock(store, "token", 5, 1, clk)
+		requireNoError(t, l.Wait(context.Background()))
+
+		retryAfter, ok := l.Reserve()
+		if ok || retryAfter != time.Minute {
+			t.Errorf("Actual: %s, %v", retryAfter, ok)
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Actual error: %v, want: %v", err, context.DeadlineExceeded)
+		}
+	})
+
+	t.Run("store_error", func(t *testing.T) {
+		errStore := errors.New("store unavailable")
+		l := NewStoreLimiter(&memoryLimiterStore{err: errStore}, "token", 5, 100)
+		if err := l.Wait(context.Background()); !errors.Is(err, errStore) {
+			t.Errorf("Actual error: %v, want: %v", err, errStore)
+		}
+		if _, ok := l.Reserve(); ok {
+			t.Error("Expected Reserve to fail")
+		}
+	})
+}
+
+func TestFileLimiterStore(t *testing.T) {
+	clk := clocktest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+	dir := t.TempDir()
+
+	// 每个协程使用独立的 FileLimiterStore，模拟多个进程
+	var (
+		granted atomic.Int32
+		wg      sync.WaitGroup
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			store, err := NewFileLimiterStore(dir)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, ok := NewStoreLimiterWithClock(store, "token", 100, 10, clk).Reserve(); ok {
+				granted.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if n := granted.Load(); n != 10 {
+		t.Errorf("Actual granted: %d, want: 10", n)
+	}
+
+	// 模拟进程重启
+	store, err := NewFileLimiterStore(dir)
+	requireNoError(t, err)
+	if _, ok := NewStoreLimiterWithClock(store, "token", 100, 10, clk).Reserve(); ok {
+		t.Error("Expected the state to survive restarts")
+	}
+	if _, ok := NewStoreLimiterWithClock(store, "other", 100, 10, clk).Reserve(); !ok {
+		t.Error("Expected other keys not to share the state")
+	}
+}
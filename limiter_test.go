@@ -0,0 +1,108 @@
+package feishu_bot_api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/electricbubble/feishu-bot-api/v2/clock"
+	"github.com/electricbubble/feishu-bot-api/v2/clock/clocktest"
+)
+
+func TestLimiterRegistry(t *testing.T) {
+	registry := NewLimiterRegistry()
+
+	b1 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(registry)).(*bot)
+	b2 := NewBot("https://open.feishu.cn/open-apis/bot/v2/hook/token-a", NewBotOptions().SetLimiterRegistry(registry)).(*bot)
+	b3 := NewBot("token-b", NewBotOptions().SetLimiterRegistry(registry)).(*bot)
+	b4 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(NewLimiterRegistry())).(*bot)
+
+	if b1.limiter != b2.limiter {
+		t.Error("Expected bots with the same webhook to share the limiter")
+	}
+	if b1.limiter == b3.limiter {
+		t.Error("Expected bots with different webhooks not to share the limiter")
+	}
+	if b1.limiter == b4.limiter {
+		t.Error("Expected bots in different registries not to share the limiter")
+	}
+
+	custom := NewLimiter(1, 1)
+	b5 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(registry).SetLimiter(custom)).(*bot)
+	if b5.limiter != custom {
+		t.Error("Expected BotOptions.Limiter to take precedence over the registry")
+	}
+
+	b6 := NewBot("token-c", NewBotOptions().SetLimiterRegistry(registry).SetLimiterPerSecond(-1)).(*bot)
+	if b6.limiter != nil {
+		t.Error("Expected the limiter to be disabled")
+	}
+
+	b7 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(registry).SetLimiterPerSecond(1)).(*bot)
+	if b1.limiter == b7.limiter {
+		t.Error("Expected bots with different limits not to share the limiter")
+	}
+
+	clk := clocktest.NewFakeClock(time.Now())
+	b8 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(registry).SetClock(clk)).(*bot)
+	b9 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(registry).SetClock(clk)).(*bot)
+	if b1.limiter == b8.limiter {
+		t.Error("Expected bots with different clocks not to share the limiter")
+	}
+	if b8.limiter != b9.limiter {
+		t.Error("Expected bots with the same clock to share the limiter")
+	}
+	if got := b8.limiter.(*windowLimiter).clock; got != clock.Clock(clk) {
+		t.Errorf("Actual limiter clock: %T, want: %T", got, clk)
+	}
+
+	// 不可比较的时钟不共享限流器
+	uc := uncomparableClock{Clock: clk}
+	b12 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(registry).SetClock(uc)).(*bot)
+	b13 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(registry).SetClock(uc)).(*bot)
+	if b12.limiter == b13.limiter {
+		t.Error("Expected bots with an uncomparable clock not to share the limiter")
+	}
+
+	registry.Delete("token-a")
+	if b10 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(registry)).(*bot); b10.limiter == b1.limiter {
+		t.Error("Expected Delete to drop the shared limiter")
+	}
+
+	explicit := NewLimiter(1, 1)
+	registry.Set("token-a", explicit)
+	if b11 := NewBot("token-a", NewBotOptions().SetLimiterRegistry(registry).SetClock(clk)).(*bot); b11.limiter != explicit {
+		t.Error("Expected the limiter from Set to be shared by all bots of the token")
+	}
+}
+
+type uncomparableClock struct {
+	clock.Clock
+	_ []int
+}
+
+func Test_windowLimiter_Reserve(t *testing.T) {
+	clk := clocktest.NewFakeClock(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	l := NewLimiterWithClock(2, 10, clk)
+	for i := 0; i < 2; i++ {
+		if _, ok := l.Reserve(); !ok {
+			t.Fatalf("Reserve(%d) failed", i)
+		}
+	}
+	if n := l.Tokens(); n > 0 {
+		t.Errorf("Actual tokens: %v, want: <= 0", n)
+	}
+
+	retryAfter, ok := l.Reserve()
+	if ok {
+		t.Fatal("Expected Reserve to fail")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("Actual retry after: %s, want: %s", retryAfter, time.Second)
+	}
+
+	clk.Advance(retryAfter)
+	if _, ok := l.Reserve(); !ok {
+		t.Error("Expected Reserve to succeed in the next second")
+	}
+}
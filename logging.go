@@ -0,0 +1,139 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// 日志中使用的属性名
+const (
+	LogKeyWebhook = "feishu.webhook"
+	LogKeyMsgType = "feishu.msg_type"
+	LogKeyCode    = "feishu.code"
+	LogKeyMsg     = "feishu.msg"
+	LogKeyStatus  = "feishu.status"
+	LogKeyAttempt = "feishu.attempt"
+	LogKeyLatency = "feishu.latency"
+	LogKeyWait    = "feishu.wait"
+	LogKeyBackoff = "feishu.backoff"
+	LogKeyBytes   = "feishu.bytes"
+	LogKeyLogID   = "feishu.log_id"
+)
+
+var _ Observer = (*slogObserver)(nil)
+
+// slogObserver 通过 Observer 事件输出日志
+type slogObserver struct {
+	logger        *slog.Logger
+	waitThreshold time.Duration
+}
+
+func (o *slogObserver) LimiterWaitStart(context.Context, LimiterWaitEvent) {}
+
+func (o *slogObserver) LimiterWaitEnd(ctx context.Context, e LimiterWaitEvent) {
+	if e.Duration < o.waitThreshold {
+		return
+	}
+	o.logger.LogAttrs(ctx, slog.LevelWarn, "feishu bot: limiter wait",
+		slog.String(LogKeyWebhook, e.Webhook),
+		slog.Duration(LogKeyWait, e.Duration),
+	)
+}
+
+func (o *slogObserver) RequestSent(ctx context.Context, e RequestEvent) {
+	o.logger.LogAttrs(ctx, slog.LevelDebug, "feishu bot: send",
+		slog.String(LogKeyWebhook, e.Webhook),
+		slog.String(LogKeyMsgType, e.MsgType),
+		slog.Int(LogKeyAttempt, e.Attempt),
+		slog.Int(LogKeyBytes, e.PayloadBytes),
+	)
+}
+
+func (o *slogObserver) ResponseReceived(ctx context.Context, e ResponseEvent) {
+	attrs := []slog.Attr{
+		slog.String(LogKeyWebhook, e.Webhook),
+		slog.String(LogKeyMsgType, e.MsgType),
+		slog.Int(LogKeyAttempt, e.Attempt),
+		slog.Duration(LogKeyLatency, e.Latency),
+	}
+	if e.LogID != "" {
+		attrs = append(attrs, slog.String(LogKeyLogID, e.LogID))
+	}
+
+	switch {
+	case e.Err == nil:
+		o.logger.LogAttrs(ctx, slog.LevelDebug, "feishu bot: sent", attrs...)
+	case e.StatusCode != 0:
+		attrs = append(attrs,
+			slog.Int(LogKeyStatus, e.StatusCode),
+			slog.Int(LogKeyCode, e.Code),
+			slog.String(LogKeyMsg, e.Msg),
+		)
+		o.logger.LogAttrs(ctx, slog.LevelWarn, "feishu bot: api error", attrs...)
+	default:
+		attrs = append(attrs, slog.Any("error", e.Err))
+		o.logger.LogAttrs(ctx, slog.LevelWarn, "feishu bot: request failed", attrs...)
+	}
+}
+
+func (o *slogObserver) Retry(ctx context.Context, e RetryEvent) {
+	o.logger.LogAttrs(ctx, slog.LevelInfo, "feishu bot: retry",
+		slog.String(LogKeyWebhook, e.Webhook),
+		slog.String(LogKeyMsgType, e.MsgType),
+		slog.Int(LogKeyAttempt, e.Attempt),
+		slog.Duration(LogKeyBackoff, e.Backoff),
+		slog.Any("error", e.Err),
+	)
+}
+
+func (o *slogObserver) Drop(ctx context.Context, e DropEvent) {
+	o.logger.LogAttrs(ctx, slog.LevelError, "feishu bot: message dropped",
+		slog.String(LogKeyWebhook, e.Webhook),
+		slog.String(LogKeyMsgType, e.MsgType),
+		slog.Int(LogKeyAttempt, e.Attempts),
+		slog.Any("error", e.Err),
+	)
+}
+
+// --------------------------------------------------------------------------------
+
+var _ Observer = multiObserver(nil)
+
+type multiObserver []Observer
+
+func (m multiObserver) LimiterWaitStart(ctx context.Context, e LimiterWaitEvent) {
+	for _, o := range m {
+		o.LimiterWaitStart(ctx, e)
+	}
+}
+
+func (m multiObserver) LimiterWaitEnd(ctx context.Context, e LimiterWaitEvent) {
+	for _, o := range m {
+		o.LimiterWaitEnd(ctx, e)
+	}
+}
+
+func (m multiObserver) RequestSent(ctx context.Context, e RequestEvent) {
+	for _, o := range m {
+		o.RequestSent(ctx, e)
+	}
+}
+
+func (m multiObserver) ResponseReceived(ctx context.Context, e ResponseEvent) {
+	for _, o := range m {
+		o.ResponseReceived(ctx, e)
+	}
+}
+
+func (m multiObserver) Retry(ctx context.Context, e RetryEvent) {
+	for _, o := range m {
+		o.Retry(ctx, e)
+	}
+}
+
+func (m multiObserver) Drop(ctx context.Context, e DropEvent) {
+	for _, o := range m {
+		o.Drop(ctx, e)
+	}
+}
@@ -0,0 +1,131 @@
+package feishu_bot_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBotOptions_Logger(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":19024,"msg":"Key Words Not Found"}`))
+	}))
+	defer srv.Close()
+
+	const token = "0123456789abcdef"
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	opts := NewBotOptions().
+		SetBaseURL(srv.URL).
+		SetLimiterRegistry(NewLimiterRegistry()).
+		SetLogger(logger)
+
+	if err := NewBot(token, opts).SendText("hi"); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var r map[string]any
+		requireNoError(t, json.Unmarshal([]byte(line), &r))
+		records = append(records, r)
+		if strings.Contains(line, token) {
+			t.Errorf("Expected the token to be masked: %s", line)
+		}
+	}
+
+	wantMsgs := []string{"feishu bot: send", "feishu bot: api error", "feishu bot: message dropped"}
+	if len(records) != len(wantMsgs) {
+		t.Fatalf("Actual records: %d, want: %d\n%s", len(records), len(wantMsgs), buf.String())
+	}
+	for i, want := range wantMsgs {
+		if msg := records[i]["msg"]; msg != want {
+			t.Errorf("Actual msg: %v, want: %s", msg, want)
+		}
+		if webhook := records[i][LogKeyWebhook]; webhook != "0123****cdef" {
+			t.Errorf("Actual webhook: %v, want: 0123****cdef", webhook)
+		}
+		if msgType := records[i][LogKeyMsgType]; msgType != "text" {
+			t.Errorf("Actual msg type: %v, want: text", msgType)
+		}
+	}
+	if code := records[1][LogKeyCode]; code != float64(CodeKeyWordsNotFound) {
+		t.Errorf("Actual code: %v, want: %d", code, CodeKeyWordsNotFound)
+	}
+	if msg := records[1][LogKeyMsg]; msg != "Key Words Not Found" {
+		t.Errorf("Actual feishu msg: %v", msg)
+	}
+}
+
+func TestBotOptions_LimiterWaitLogThreshold(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	opts := NewBotOptions().
+		SetBaseURL(srv.URL).
+		SetLimiterRegistry(NewLimiterRegistry()).
+		SetLimiterPerSecond(1).
+		SetLimiterWaitLogThreshold(time.Millisecond).
+		SetLogger(slog.New(slog.NewJSONHandler(&buf, nil)))
+
+	b := NewBot("tmp", opts)
+	requireNoError(t, b.SendText("hi"))
+	if buf.Len() != 0 {
+		t.Errorf("Expected no logs: %s", buf.String())
+	}
+	requireNoError(t, b.SendText("hi"))
+	if !strings.Contains(buf.String(), `"msg":"feishu bot: limiter wait"`) {
+		t.Errorf("Expected a limiter wait log: %s", buf.String())
+	}
+}
+
+func Test_bot_redactURLError(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	tests := []struct {
+		name    string
+		token   string
+		wantURL string
+	}{
+		{
+			name:    "token",
+			token:   "0123456789abcdef",
+			wantURL: srv.URL + "/open-apis/bot/v2/hook/0123****cdef",
+		},
+		{
+			name:    "short_token",
+			token:   "open",
+			wantURL: srv.URL + "/open-apis/bot/v2/hook/****",
+		},
+		{
+			name:    "empty_token",
+			token:   "",
+			wantURL: srv.URL + "/open-apis/bot/v2/hook",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := NewBot(tt.token, NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())).SendText("hi")
+
+			var ue *url.Error
+			if !errors.As(err, &ue) {
+				t.Fatalf("Actual error: %v, want: %T", err, ue)
+			}
+			if got := strings.TrimSuffix(ue.URL, "/"); got != tt.wantURL {
+				t.Errorf("Actual url: %s, want: %s", ue.URL, tt.wantURL)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package md
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // https://open.feishu.cn/document/common-capabilities/message-card/message-cards-content/using-markdown-tags?lang=zh-CN#abc9b025
 
@@ -95,3 +98,22 @@ func GreyText(s string) string {
 func TextTag(color TextTagColor, s string) string {
 	return fmt.Sprintf("<text_tag color='%s'>%s</text_tag>", color, s)
 }
+
+var _escaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"*", "&#42;",
+	"~", "&#126;",
+	"_", "&#95;",
+	"`", "&#96;",
+	"[", "&#91;",
+	"]", "&#93;",
+)
+
+// Escape 转义 lark_md 中有特殊含义的字符，使 s 按原文展示
+//
+// https://open.feishu.cn/document/common-capabilities/message-card/message-cards-content/using-markdown-tags?lang=zh-CN#abc9b025
+func Escape(s string) string {
+	return _escaper.Replace(s)
+}
@@ -0,0 +1,143 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Sender 发送消息
+type Sender interface {
+	SendMessage(ctx context.Context, msg Message) error
+}
+
+// SenderFunc 函数形式的 Sender
+type SenderFunc func(ctx context.Context, msg Message) error
+
+func (f SenderFunc) SendMessage(ctx context.Context, msg Message) error {
+	return f(ctx, msg)
+}
+
+// Middleware 包装 SendMessage 的中间件，可用于脱敏、追加页脚、埋点、审计等
+//
+// 多个中间件按 BotOptions.Middlewares 的顺序由外到内执行，即第一个中间件最先看到消息、最后看到结果。
+// 中间件包裹的是整个发送流程（包括重试），单次请求相关的处理参考 BeforeHTTP、AfterResponse
+type Middleware func(next Sender) Sender
+
+func chainMiddlewares(middlewares []Middleware, s Sender) Sender {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		s = middlewares[i](s)
+	}
+	return s
+}
+
+// BeforeApply 在消息转换为 MessageBody 之前执行，可以替换消息
+func BeforeApply(f func(ctx context.Context, msg Message) (Message, error)) Middleware {
+	return func(next Sender) Sender {
+		return SenderFunc(func(ctx context.Context, msg Message) error {
+			msg, err := f(ctx, msg)
+			if err != nil {
+				return fmt.Errorf("hook(BeforeApply): %w", err)
+			}
+			return next.SendMessage(ctx, msg)
+		})
+	}
+}
+
+// AfterApply 在消息转换为 MessageBody 之后执行，可以修改 MessageBody
+func AfterApply(f func(ctx context.Context, body *MessageBody) error) Middleware {
+	return func(next Sender) Sender {
+		return SenderFunc(func(ctx context.Context, msg Message) error {
+			return next.SendMessage(ctx, afterApplyMessage{Message: msg, ctx: ctx, f: f})
+		})
+	}
+}
+
+type afterApplyMessage struct {
+	Message
+	ctx context.Context
+	f   func(ctx context.Context, body *MessageBody) error
+}
+
+func (m afterApplyMessage) Apply(body *MessageBody) error {
+	if err := m.Message.Apply(body); err != nil {
+		return err
+	}
+	if err := m.f(m.ctx, body); err != nil {
+		return fmt.Errorf("hook(AfterApply): %w", err)
+	}
+	return nil
+}
+
+// BeforeHTTP 在每次发送 HTTP 请求之前执行（重试时每次都会执行），此时已生成签名
+//
+// 返回 error 时不发送请求
+func BeforeHTTP(f func(ctx context.Context, req *APIRequest) error) Middleware {
+	return func(next Sender) Sender {
+		return SenderFunc(func(ctx context.Context, msg Message) error {
+			return next.SendMessage(withSendHooks(ctx, func(h *sendHooks) {
+				h.beforeHTTP = append(h.beforeHTTP, f)
+			}), msg)
+		})
+	}
+}
+
+// ResponseInfo 单次 HTTP 请求的结果
+type ResponseInfo struct {
+	Request *APIRequest
+	// Response 请求失败（例如网络错误）时为 nil
+	Response   *APIResponse
+	StatusCode int
+	// LogID 响应头 X-Tt-Logid
+	LogID   string
+	Latency time.Duration
+	Err     error
+}
+
+// AfterResponse 在每次 HTTP 请求结束之后执行（重试时每次都会执行）
+//
+// 多个 AfterResponse 按由内到外的顺序执行
+func AfterResponse(f func(ctx context.Context, info ResponseInfo)) Middleware {
+	return func(next Sender) Sender {
+		return SenderFunc(func(ctx context.Context, msg Message) error {
+			return next.SendMessage(withSendHooks(ctx, func(h *sendHooks) {
+				h.afterResponse = append(h.afterResponse, f)
+			}), msg)
+		})
+	}
+}
+
+// --------------------------------------------------------------------------------
+
+type sendHooksKey struct{}
+
+// sendHooks 通过 ctx 传递给 bot.send 的钩子，参考 net/http/httptrace
+type sendHooks struct {
+	beforeHTTP    []func(ctx context.Context, req *APIRequest) error
+	afterResponse []func(ctx context.Context, info ResponseInfo)
+}
+
+func sendHooksFromContext(ctx context.Context) *sendHooks {
+	if h, ok := ctx.Value(sendHooksKey{}).(*sendHooks); ok {
+		return h
+	}
+	return &sendHooks{}
+}
+
+// withSendHooks 复制 ctx 中已有的钩子后调用 fn 追加，不影响外层 ctx
+func withSendHooks(ctx context.Context, fn func(h *sendHooks)) context.Context {
+	old := sendHooksFromContext(ctx)
+	h := &sendHooks{
+		beforeHTTP:    slices.Clone(old.beforeHTTP),
+		afterResponse: slices.Clone(old.afterResponse),
+	}
+	fn(h)
+	return context.WithValue(ctx, sendHooksKey{}, h)
+}
+
+func (h *sendHooks) runAfterResponse(ctx context.Context, info ResponseInfo) {
+	for i := len(h.afterResponse) - 1; i >= 0; i-- {
+		h.afterResponse[i](ctx, info)
+	}
+}
@@ -0,0 +1,151 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received []string
+		attempts int
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req APIRequest
+		_ = json.NewDecoder(r.Body).Decode(&req)
+
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, req.Content.Text)
+		attempts++
+		if attempts == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+	defer srv.Close()
+
+	var calls []string
+	trace := func(name string) Middleware {
+		return func(next Sender) Sender {
+			return SenderFunc(func(ctx context.Context, msg Message) error {
+				calls = append(calls, name+":before")
+				err := next.SendMessage(ctx, msg)
+				calls = append(calls, name+":after")
+				return err
+			})
+		}
+	}
+
+	var responses []ResponseInfo
+	opts := NewBotOptions().
+		SetBaseURL(srv.URL).
+		SetSecretKey("secret").
+		SetLimiterRegistry(NewLimiterRegistry()).
+		SetRetry(NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond)).
+		Use(
+			trace("outer"),
+			BeforeApply(func(ctx context.Context, msg Message) (Message, error) {
+				return textMessage(strings.ReplaceAll(string(msg.(textMessage)), "password", "******")), nil
+			}),
+			AfterApply(func(ctx context.Context, body *MessageBody) error {
+				body.Content.Text += "\n-- sent by ci"
+				return nil
+			}),
+			BeforeHTTP(func(ctx context.Context, req *APIRequest) error {
+				if req.Sign == "" || req.Timestamp == 0 {
+					t.Error("Expected the request to be signed")
+				}
+				calls = append(calls, "before_http")
+				return nil
+			}),
+			AfterResponse(func(ctx context.Context, info ResponseInfo) {
+				calls = append(calls, "after_response")
+				responses = append(responses, info)
+			}),
+			trace("inner"),
+		)
+
+	requireNoError(t, NewBot("tmp", opts).SendText("password: 123"))
+
+	if want := []string{"******: 123\n-- sent by ci", "******: 123\n-- sent by ci"}; !reflect.DeepEqual(received, want) {
+		t.Errorf("Actual received: %q, want: %q", received, want)
+	}
+
+	wantCalls := []string{
+		"outer:before", "inner:before",
+		"before_http", "after_response",
+		"before_http", "after_response",
+		"inner:after", "outer:after",
+	}
+	if !reflect.DeepEqual(calls, wantCalls) {
+		t.Errorf("Actual calls: %q, want: %q", calls, wantCalls)
+	}
+
+	if len(responses) != 2 {
+		t.Fatalf("Actual responses: %d, want: 2", len(responses))
+	}
+	if r := responses[0]; r.StatusCode != http.StatusInternalServerError || r.Err == nil {
+		t.Errorf("Actual first response: %d, %v", r.StatusCode, r.Err)
+	}
+	if r := responses[1]; r.StatusCode != http.StatusOK || r.Err != nil || r.Response == nil || r.Response.Msg != "success" {
+		t.Errorf("Actual second response: %+v", r)
+	}
+}
+
+func TestMiddleware_abort(t *testing.T) {
+	var sent bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = true
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+	defer srv.Close()
+
+	errAbort := errors.New("abort")
+	tests := []struct {
+		name       string
+		middleware Middleware
+	}{
+		{
+			name: "before_apply",
+			middleware: BeforeApply(func(ctx context.Context, msg Message) (Message, error) {
+				return nil, errAbort
+			}),
+		},
+		{
+			name: "after_apply",
+			middleware: AfterApply(func(ctx context.Context, body *MessageBody) error {
+				return errAbort
+			}),
+		},
+		{
+			name: "before_http",
+			middleware: BeforeHTTP(func(ctx context.Context, req *APIRequest) error {
+				return errAbort
+			}),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			sent = false
+			opts := NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).Use(tt.middleware)
+			if err := NewBot("tmp", opts).SendText("hi"); !errors.Is(err, errAbort) {
+				t.Errorf("Actual error: %v, want: %v", err, errAbort)
+			}
+			if sent {
+				t.Error("Expected the request not to be sent")
+			}
+		})
+	}
+}
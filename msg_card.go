@@ -13,6 +13,9 @@ type cardMessage struct {
 	globalConf *CardGlobalConfig
 
 	builders []*CardBuilder
+
+	// plain 由 ParseMessageBody 还原非多语言的卡片时设置，Apply 时保持 header.title.content、elements 结构
+	plain bool
 }
 
 func (m cardMessage) Apply(body *MessageBody) error {
@@ -24,17 +27,33 @@ func (m cardMessage) Apply(body *MessageBody) error {
 		CardLink:     nil,
 	}
 
-	rawHeader, err := json.Marshal(m.buildHeader())
-	if err != nil {
-		return fmt.Errorf("card message: marshal header: %w", err)
-	}
-	card.Header = (*json.RawMessage)(&rawHeader)
+	if cb := m.plainBuilder(); cb != nil {
+		rawHeader, err := json.Marshal(m.buildPlainHeader(cb))
+		if err != nil {
+			return fmt.Errorf("card message: marshal header: %w", err)
+		}
+		card.Header = (*json.RawMessage)(&rawHeader)
 
-	rawI18nElements, err := m.marshalI18nElements()
-	if err != nil {
-		return fmt.Errorf("card message: marshal i18n_elements: %w", err)
+		if cb.elements != nil {
+			rawElements, err := json.Marshal(cb.elements)
+			if err != nil {
+				return fmt.Errorf("card message: marshal elements: %w", err)
+			}
+			card.Elements = (*json.RawMessage)(&rawElements)
+		}
+	} else {
+		rawHeader, err := json.Marshal(m.buildHeader())
+		if err != nil {
+			return fmt.Errorf("card message: marshal header: %w", err)
+		}
+		card.Header = (*json.RawMessage)(&rawHeader)
+
+		rawI18nElements, err := m.marshalI18nElements()
+		if err != nil {
+			return fmt.Errorf("card message: marshal i18n_elements: %w", err)
+		}
+		card.I18nElements = &rawI18nElements
 	}
-	card.I18nElements = &rawI18nElements
 
 	if m.globalConf != nil && m.globalConf.config != nil {
 		raw, err := json.Marshal(m.globalConf.config)
@@ -62,6 +81,41 @@ func (m cardMessage) Apply(body *MessageBody) error {
 	return nil
 }
 
+// plainBuilder 非多语言的卡片（plain）只有一个未指定语言的 CardBuilder 时返回该构建器，使用 header.title.content、elements 结构
+func (m cardMessage) plainBuilder() *CardBuilder {
+	if !m.plain {
+		return nil
+	}
+	var ret *CardBuilder
+	for i := range m.builders {
+		if m.builders[i] == nil {
+			continue
+		}
+		if ret != nil || m.builders[i].language != "" {
+			return nil
+		}
+		ret = m.builders[i]
+	}
+	return ret
+}
+
+func (m cardMessage) buildPlainHeader(cb *CardBuilder) cardPlainHeader {
+	ret := cardPlainHeader{
+		Title: cardHeaderComponentPlainText{Tag: "plain_text", Content: cb.headerTitleContent},
+	}
+	if cb.headerSubtitleContent != "" {
+		ret.Subtitle = &cardHeaderComponentPlainText{Tag: "plain_text", Content: cb.headerSubtitleContent}
+	}
+	if m.globalConf != nil {
+		ret.Icon = m.globalConf.headerIcon
+		ret.Template = m.globalConf.headerTemplate
+	}
+	if cb.headerI18nTextTags != nil {
+		ret.TextTagList = *cb.headerI18nTextTags
+	}
+	return ret
+}
+
 func (m cardMessage) buildHeader() cardHeader {
 	nnBuilders := make([]*CardBuilder, 0, len(m.builders))
 	for i := range m.builders {
@@ -368,6 +422,16 @@ func (cb *CardBuilder) Elements(elements []CardElement) *CardBuilder {
 		// 标签展示顺序与数组顺序一致
 		I18nTextTagList *cardHeaderI18nTextTags `json:"i18n_text_tag_list,omitempty"`
 	}
+	// cardPlainHeader 非多语言的卡片标题
+	cardPlainHeader struct {
+		Title    cardHeaderComponentPlainText  `json:"title"`
+		Subtitle *cardHeaderComponentPlainText `json:"subtitle,omitempty"`
+		Icon     *cardHeaderIcon               `json:"icon,omitempty"`
+		Template CardHeaderTemplate            `json:"template,omitempty"`
+
+		// 标题标签，最多展示 3 个
+		TextTagList []cardHeaderI18nTextTag `json:"text_tag_list,omitempty"`
+	}
 	cardHeaderTitle struct {
 		// 文本标识。固定取值：plain_text
 		Tag string `json:"tag"`
@@ -603,6 +667,7 @@ type CardElement interface {
 	_ CardElement = (*CardElementHorizontalRule)(nil)
 	_ CardElement = (*CardElementImage)(nil)
 	_ CardElement = (*CardElementNote)(nil)
+	_ CardElement = CardElementRaw(nil)
 )
 
 // ----------------------------------------
@@ -792,6 +857,15 @@ func (e *CardElementMarkdown) Href(defaultURL, pc, ios, android string) *CardEle
 
 // ----------------------------------------
 
+// CardElementRaw 以原始 JSON 表示的卡片元素，例如 ParseMessageBody 中无法还原的元素
+type CardElementRaw json.RawMessage
+
+func (e CardElementRaw) Entity() any {
+	return json.RawMessage(e)
+}
+
+// ----------------------------------------
+
 // CardElementHorizontalRule 分割线
 //
 // https://open.feishu.cn/document/common-capabilities/message-card/message-cards-content/divider-line-module
@@ -0,0 +1,246 @@
+package feishu_bot_api
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/electricbubble/feishu-bot-api/v2/internal/mdparse"
+	"github.com/electricbubble/feishu-bot-api/v2/md"
+)
+
+// MarkdownImageKeyFunc 将 Markdown 中的图片地址转换为 image_key，ok 为 false 时图片以链接展示
+//
+// 上传图片获取 image_key: https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/image/create
+type MarkdownImageKeyFunc func(src string) (imgKey string, ok bool)
+
+// NewCardElementsFromMarkdown 将 Markdown（CommonMark 以及 GFM 删除线、表格）转换为卡片元素，可直接用于 CardBuilder.Elements、CardV2Builder.Elements
+//
+//   - 标题: 加粗的 CardElementMarkdown
+//   - 分割线: NewCardElementHorizontalRule
+//   - 单独成段的图片: CardElementImage；其他图片使用 lark_md 的图片语法
+//   - 表格: 每行一个 CardElementColumnSet，表头为灰底、加粗
+//   - 引用: 以 &gt; 开头的 CardElementMarkdown（卡片 JSON 2.0 不支持 CardElementNote）
+//   - 段落、列表、代码块: CardElementMarkdown，文本中的特殊字符按 lark_md 转义
+//
+// imageKey 为 nil 或无法转换时，图片以 [替代文本](图片地址) 的链接展示
+//
+// https://open.feishu.cn/document/common-capabilities/message-card/message-cards-content/using-markdown-tags
+func NewCardElementsFromMarkdown(markdown string, imageKey MarkdownImageKeyFunc) []CardElement {
+	c := cardMarkdownConverter{imageKey: imageKey}
+	c.convert(mdparse.Parse(markdown))
+	return c.elements
+}
+
+type cardMarkdownConverter struct {
+	imageKey MarkdownImageKeyFunc
+	elements []CardElement
+}
+
+func (c *cardMarkdownConverter) convert(blocks []mdparse.Block) {
+	for _, block := range blocks {
+		switch block.Kind {
+		case mdparse.BlockHeading:
+			c.elements = append(c.elements, NewCardElementMarkdown(c.larkMarkdownInlines(block.Inlines, mdparse.StyleBold)))
+		case mdparse.BlockParagraph:
+			if img, ok := singleImage(block.Inlines); ok {
+				if key, ok := c.lookupImage(img.Src); ok {
+					c.elements = append(c.elements, NewCardElementImage(key, img.Text))
+					continue
+				}
+			}
+			c.elements = append(c.elements, NewCardElementMarkdown(c.larkMarkdownInlines(block.Inlines, 0)))
+		case mdparse.BlockThematicBreak:
+			c.elements = append(c.elements, NewCardElementHorizontalRule())
+		case mdparse.BlockTable:
+			c.elements = append(c.elements, c.table(block)...)
+		default:
+			c.elements = append(c.elements, NewCardElementMarkdown(c.larkMarkdown([]mdparse.Block{block}, "\n\n")))
+		}
+	}
+}
+
+func (c *cardMarkdownConverter) table(block mdparse.Block) []CardElement {
+	row := func(cells [][]mdparse.Inline, header bool) CardElement {
+		cs := NewCardElementColumnSet().FlexMode(CardElementColumnSetFlexModeNone)
+		style := mdparse.Style(0)
+		if header {
+			cs.BackgroundStyle(CardElementColumnSetBackgroundStyleGrey)
+			style = mdparse.StyleBold
+		}
+
+		columns := make([]*CardElementColumnSetColumn, 0, len(cells))
+		for i := range cells {
+			content := c.larkMarkdownInlines(cells[i], style)
+			if content == "" {
+				content = "&nbsp;"
+			}
+			e := NewCardElementMarkdown(content)
+			switch block.Aligns[i] {
+			case mdparse.AlignLeft:
+				e.TextAlign(CardElementMarkdownTextAlignLeft)
+			case mdparse.AlignCenter:
+				e.TextAlign(CardElementMarkdownTextAlignCenter)
+			case mdparse.AlignRight:
+				e.TextAlign(CardElementMarkdownTextAlignRight)
+			}
+			columns = append(columns, NewCardElementColumnSetColumn().
+				Width(CardElementColumnSetColumnWidthWeighted).
+				Weight(1).
+				Elements([]CardElement{e}))
+		}
+		return cs.Columns(columns)
+	}
+
+	ret := make([]CardElement, 0, len(block.Rows)+1)
+	ret = append(ret, row(block.Header, true))
+	for _, cells := range block.Rows {
+		ret = append(ret, row(cells, false))
+	}
+	return ret
+}
+
+// larkMarkdown 将块转换为 lark_md 文本，sep 为块之间的分隔
+func (c *cardMarkdownConverter) larkMarkdown(blocks []mdparse.Block, sep string) string {
+	parts := make([]string, 0, len(blocks))
+	for _, block := range blocks {
+		switch block.Kind {
+		case mdparse.BlockParagraph:
+			parts = append(parts, c.larkMarkdownInlines(block.Inlines, 0))
+		case mdparse.BlockHeading:
+			parts = append(parts, c.larkMarkdownInlines(block.Inlines, mdparse.StyleBold))
+		case mdparse.BlockCode:
+			parts = append(parts, "```"+block.Language+"\n"+block.Code+"\n```")
+		case mdparse.BlockThematicBreak:
+			parts = append(parts, strings.TrimSpace(md.HorizontalRule()))
+		case mdparse.BlockList:
+			items := make([]string, 0, len(block.Items))
+			for i, item := range block.Items {
+				marker := "- "
+				if block.Ordered {
+					marker = strconv.Itoa(block.Start+i) + ". "
+				}
+				items = append(items, prefixLines(c.larkMarkdown(item, "\n"), marker, strings.Repeat(" ", utf8.RuneCountInString(marker))))
+			}
+			parts = append(parts, strings.Join(items, "\n"))
+		case mdparse.BlockQuote:
+			// lark_md 不支持引用，以 > 开头的文本展示
+			parts = append(parts, prefixLines(c.larkMarkdown(block.Children, "\n"), "&gt; ", "&gt; "))
+		case mdparse.BlockTable:
+			// 嵌套在列表、引用中的表格，每行以 | 分隔
+			rows := make([]string, 0, len(block.Rows)+1)
+			for i, cells := range append([][][]mdparse.Inline{block.Header}, block.Rows...) {
+				style := mdparse.Style(0)
+				if i == 0 {
+					style = mdparse.StyleBold
+				}
+				texts := make([]string, 0, len(cells))
+				for _, cell := range cells {
+					texts = append(texts, c.larkMarkdownInlines(cell, style))
+				}
+				rows = append(rows, strings.Join(texts, " | "))
+			}
+			parts = append(parts, strings.Join(rows, "\n"))
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func (c *cardMarkdownConverter) larkMarkdownInlines(inlines []mdparse.Inline, base mdparse.Style) string {
+	var sb strings.Builder
+	for _, inl := range inlines {
+		switch inl.Kind {
+		case mdparse.InlineSoftBreak:
+			sb.WriteString(" ")
+		case mdparse.InlineHardBreak:
+			sb.WriteString(md.LineBreak())
+		case mdparse.InlineCode:
+			sb.WriteString("`" + inl.Text + "`")
+		case mdparse.InlineImage:
+			if key, ok := c.lookupImage(inl.Src); ok {
+				sb.WriteString(md.Image(key, md.Escape(inl.Text)))
+				continue
+			}
+			text := inl.Text
+			if text == "" {
+				text = inl.Src
+			}
+			sb.WriteString(md.TextLink(wrapLarkMarkdownStyle(md.Escape(text), inl.Style|base), larkMarkdownURL(inl.Src)))
+		default:
+			s := wrapLarkMarkdownStyle(md.Escape(inl.Text), inl.Style|base)
+			if inl.Link != "" {
+				s = md.TextLink(s, larkMarkdownURL(inl.Link))
+			}
+			sb.WriteString(s)
+		}
+	}
+	return sb.String()
+}
+
+func (c *cardMarkdownConverter) lookupImage(src string) (string, bool) {
+	if c.imageKey == nil {
+		return "", false
+	}
+	key, ok := c.imageKey(src)
+	return key, ok && key != ""
+}
+
+// singleImage 段落是否只包含一张图片
+func singleImage(inlines []mdparse.Inline) (mdparse.Inline, bool) {
+	var (
+		img   mdparse.Inline
+		found bool
+	)
+	for _, inl := range inlines {
+		switch {
+		case inl.Kind == mdparse.InlineImage && !found:
+			img, found = inl, true
+		case inl.Kind == mdparse.InlineText && strings.TrimSpace(inl.Text) == "",
+			inl.Kind == mdparse.InlineSoftBreak, inl.Kind == mdparse.InlineHardBreak:
+		default:
+			return mdparse.Inline{}, false
+		}
+	}
+	return img, found
+}
+
+// wrapLarkMarkdownStyle 为 s 添加样式，首尾的空格放在样式标记之外
+func wrapLarkMarkdownStyle(s string, style mdparse.Style) string {
+	core := strings.TrimSpace(s)
+	if core == "" || style == 0 {
+		return s
+	}
+	lead := s[:strings.Index(s, core)]
+	trail := s[len(lead)+len(core):]
+
+	if style&mdparse.StyleStrikethrough != 0 {
+		core = md.Strikethrough(core)
+	}
+	if style&mdparse.StyleItalic != 0 {
+		core = md.Italic(core)
+	}
+	if style&mdparse.StyleBold != 0 {
+		core = md.Bold(core)
+	}
+	return lead + core + trail
+}
+
+var _larkMarkdownURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+
+func larkMarkdownURL(u string) string {
+	return _larkMarkdownURLEscaper.Replace(u)
+}
+
+// prefixLines 第一行添加 first 前缀，其余非空行添加 rest 前缀
+func prefixLines(s, first, rest string) string {
+	lines := strings.Split(s, "\n")
+	for i := range lines {
+		switch {
+		case i == 0:
+			lines[i] = first + lines[i]
+		case lines[i] != "":
+			lines[i] = rest + lines[i]
+		}
+	}
+	return strings.Join(lines, "\n")
+}
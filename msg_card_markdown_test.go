@@ -0,0 +1,82 @@
+package feishu_bot_api
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestNewCardElementsFromMarkdown(t *testing.T) {
+	const markdown = "# Incident *42*\n" +
+		"\n" +
+		"Root cause: `nil` map in **api** (see [PR](https://example.com/pr/a(1))) 2*3*4_5_\n" +
+		"\n" +
+		"![chart](https://example.com/chart.png)\n" +
+		"\n" +
+		"![missing](https://example.com/missing.png)\n" +
+		"\n" +
+		"---\n" +
+		"\n" +
+		"| Service | Status |\n" +
+		"|:--|:-:|\n" +
+		"| api | ~~down~~ |\n" +
+		"| web |\n" +
+		"\n" +
+		"- one\n" +
+		"- two\n" +
+		"  1. nested\n" +
+		"\n" +
+		"> quoted <b>\n" +
+		"\n" +
+		"```go\n" +
+		"panic(err)\n" +
+		"```"
+
+	keys := map[string]string{"https://example.com/chart.png": "img_chart"}
+	elements := NewCardElementsFromMarkdown(markdown, func(src string) (string, bool) {
+		key, ok := keys[src]
+		return key, ok
+	})
+
+	want := []string{
+		`{"tag":"markdown","content":"**Incident** ***42***"}`,
+		`{"tag":"markdown","content":"Root cause: ` + "`nil`" + ` map in **api** (see [PR](https://example.com/pr/a%281%29)) 2*3*4&#95;5&#95;"}`,
+		`{"tag":"img","img_key":"img_chart","alt":{"tag":"plain_text","content":"chart"}}`,
+		`{"tag":"markdown","content":"[missing](https://example.com/missing.png)"}`,
+		`{"tag":"hr"}`,
+		`{"tag":"column_set","flex_mode":"none","background_style":"grey","columns":[` +
+			`{"tag":"column","width":"weighted","weight":1,"elements":[{"tag":"markdown","content":"**Service**","text_align":"left"}]},` +
+			`{"tag":"column","width":"weighted","weight":1,"elements":[{"tag":"markdown","content":"**Status**","text_align":"center"}]}]}`,
+		`{"tag":"column_set","flex_mode":"none","columns":[` +
+			`{"tag":"column","width":"weighted","weight":1,"elements":[{"tag":"markdown","content":"api","text_align":"left"}]},` +
+			`{"tag":"column","width":"weighted","weight":1,"elements":[{"tag":"markdown","content":"~~down~~","text_align":"center"}]}]}`,
+		`{"tag":"column_set","flex_mode":"none","columns":[` +
+			`{"tag":"column","width":"weighted","weight":1,"elements":[{"tag":"markdown","content":"web","text_align":"left"}]},` +
+			`{"tag":"column","width":"weighted","weight":1,"elements":[{"tag":"markdown","content":"&nbsp;","text_align":"center"}]}]}`,
+		`{"tag":"markdown","content":"- one\n- two\n  1. nested"}`,
+		`{"tag":"markdown","content":"&gt; quoted &lt;b&gt;"}`,
+		"{\"tag\":\"markdown\",\"content\":\"```go\\npanic(err)\\n```\"}",
+	}
+
+	if len(elements) != len(want) {
+		for _, e := range elements {
+			raw, _ := json.Marshal(e.Entity())
+			t.Logf("%s", raw)
+		}
+		t.Fatalf("Actual elements: %d, want: %d", len(elements), len(want))
+	}
+	for i := range want {
+		got, err := json.Marshal(elements[i].Entity())
+		requireNoError(t, err)
+		if !jsonEqual(got, []byte(want[i])) {
+			t.Errorf("element(%d): Actual:\n%s\nwant:\n%s", i, got, want[i])
+		}
+	}
+
+	// 可直接用于 CardBuilder.Elements、CardV2Builder.Elements
+	var body MessageBody
+	requireNoError(t, cardMessage{
+		globalConf: NewCardGlobalConfig(),
+		builders:   []*CardBuilder{NewCard(LanguageChinese, "复盘").Elements(elements)},
+	}.Apply(&body))
+	requireNoError(t, cardMessageV2{card: NewCardV2().Elements(elements)}.Apply(&body))
+}
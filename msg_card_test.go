@@ -262,3 +262,22 @@ func Test_cardMessage_buildHeader_output(t *testing.T) {
 	t.Logf("\n%s", bs)
 
 }
+
+func Test_cardMessage_Apply_emptyLanguage(t *testing.T) {
+	// 未指定语言的 CardBuilder 仍然使用多语言的卡片结构，只有 ParseMessageBody 还原的非多语言卡片才使用 header.title.content、elements
+	m := cardMessage{
+		globalConf: NewCardGlobalConfig().HeaderTemplate(CardHeaderTemplateRed),
+		builders: []*CardBuilder{
+			NewCard("", "告警").HeaderSubtitle("api").Elements([]CardElement{NewCardElementMarkdown("内容")}),
+		},
+	}
+
+	var body MessageBody
+	requireNoError(t, m.Apply(&body))
+
+	want := `{"header":{"title":{"tag":"plain_text","i18n":{"":"告警"}},"subtitle":{"tag":"plain_text","i18n":{"":"api"}},"template":"red"},` +
+		`"i18n_elements":{"":[{"tag":"markdown","content":"内容"}]}}`
+	if got := []byte(*body.Card); !jsonEqual(got, []byte(want)) {
+		t.Errorf("Actual:\n%s\nwant:\n%s", got, want)
+	}
+}
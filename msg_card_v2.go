@@ -0,0 +1,389 @@
+package feishu_bot_api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var _ Message = (*cardMessageV2)(nil)
+
+// cardMessageV2 卡片 JSON 2.0
+//
+// https://open.feishu.cn/document/uAjLw4CM/ukzMukzMukzM/feishu-cards/card-json-v2-structure
+type cardMessageV2 struct {
+	card *CardV2Builder
+}
+
+// ErrCardV2UnsupportedElement 卡片 JSON 2.0 不再支持的组件，例如备注（note）、交互模块（action）
+var ErrCardV2UnsupportedElement = errors.New("element is not supported in card JSON 2.0")
+
+func (m cardMessageV2) Apply(body *MessageBody) error {
+	if m.card == nil {
+		return errors.New("card message(2.0): nil card")
+	}
+	for i := range m.card.card.Body.Elements {
+		if err := validateCardV2Element(m.card.card.Body.Elements[i]); err != nil {
+			return fmt.Errorf("card message(2.0): element(%d): %w", i, err)
+		}
+	}
+
+	raw, err := json.Marshal(m.card.card)
+	if err != nil {
+		return fmt.Errorf("card message(2.0): marshal: %w", err)
+	}
+
+	body.MsgType = "interactive"
+	body.Card = (*json.RawMessage)(&raw)
+	return nil
+}
+
+func validateCardV2Element(entity any) error {
+	switch e := entity.(type) {
+	case cardElementNote:
+		return fmt.Errorf("%w: %s", ErrCardV2UnsupportedElement, e.Tag)
+	case cardElementAction:
+		return fmt.Errorf("%w: %s", ErrCardV2UnsupportedElement, e.Tag)
+	case cardElementColumnSet:
+		for _, column := range e.Columns {
+			for i := range column.Elements {
+				if err := validateCardV2Element(column.Elements[i]); err != nil {
+					return fmt.Errorf("column_set: %w", err)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// --------------------------------------------------------------------------------
+
+type (
+	// CardV2Builder 卡片 JSON 2.0 构建器，正文中可以复用 CardElementMarkdown、CardElementDiv、CardElementImage、
+	// CardElementHorizontalRule、CardElementColumnSet 等组件；CardElementNote、CardElementAction 在 2.0 中已不再支持
+	//
+	// https://open.feishu.cn/document/uAjLw4CM/ukzMukzMukzM/feishu-cards/card-json-v2-structure
+	CardV2Builder struct {
+		card cardV2
+	}
+
+	cardV2 struct {
+		// 卡片结构的版本，固定取值：2.0
+		Schema   string        `json:"schema"`
+		Config   *cardV2Config `json:"config,omitempty"`
+		CardLink *cardLink     `json:"card_link,omitempty"`
+		Header   *cardV2Header `json:"header,omitempty"`
+		Body     cardV2Body    `json:"body"`
+	}
+
+	cardV2Config struct {
+		// 是否开启流式更新模式
+		StreamingMode *bool `json:"streaming_mode,omitempty"`
+
+		// 卡片的摘要信息，展示在会话列表中
+		Summary *cardV2Text `json:"summary,omitempty"`
+
+		// 卡片生效的语言列表
+		Locales []Language `json:"locales,omitempty"`
+
+		// 是否允许转发卡片
+		EnableForward *bool `json:"enable_forward,omitempty"`
+
+		// 是否为共享卡片
+		UpdateMulti *bool `json:"update_multi,omitempty"`
+
+		// 卡片宽度模式
+		WidthMode CardV2WidthMode `json:"width_mode,omitempty"`
+	}
+
+	// cardV2Text 文本及其多语言内容
+	cardV2Text struct {
+		Tag         string              `json:"tag,omitempty"`
+		Content     string              `json:"content,omitempty"`
+		I18nContent map[Language]string `json:"i18n_content,omitempty"`
+	}
+
+	cardV2Header struct {
+		Title           *cardV2Text                          `json:"title,omitempty"`
+		Subtitle        *cardV2Text                          `json:"subtitle,omitempty"`
+		TextTagList     []cardHeaderI18nTextTag              `json:"text_tag_list,omitempty"`
+		I18nTextTagList map[Language][]cardHeaderI18nTextTag `json:"i18n_text_tag_list,omitempty"`
+		Template        CardHeaderTemplate                   `json:"template,omitempty"`
+		Icon            *cardV2HeaderIcon                    `json:"icon,omitempty"`
+		Padding         string                               `json:"padding,omitempty"`
+	}
+
+	cardV2HeaderIcon struct {
+		// standard_icon：图标库中的图标；custom_icon：自定义图片
+		Tag    string `json:"tag"`
+		Token  string `json:"token,omitempty"`
+		Color  string `json:"color,omitempty"`
+		ImgKey string `json:"img_key,omitempty"`
+	}
+
+	cardV2Body struct {
+		Direction         CardV2Direction `json:"direction,omitempty"`
+		Padding           string          `json:"padding,omitempty"`
+		HorizontalSpacing string          `json:"horizontal_spacing,omitempty"`
+		VerticalSpacing   string          `json:"vertical_spacing,omitempty"`
+		Elements          []any           `json:"elements"`
+	}
+)
+
+// CardV2WidthMode 卡片宽度模式
+//   - default：默认宽度，PC 端宽版、iPad 端上的宽度上限为 600px
+//   - fill：自适应屏幕宽度
+type CardV2WidthMode string
+
+const (
+	CardV2WidthModeDefault CardV2WidthMode = "default"
+	CardV2WidthModeFill    CardV2WidthMode = "fill"
+)
+
+// CardV2Direction 正文中组件的排列方向
+type CardV2Direction string
+
+const (
+	CardV2DirectionVertical   CardV2Direction = "vertical"
+	CardV2DirectionHorizontal CardV2Direction = "horizontal"
+)
+
+func NewCardV2() *CardV2Builder {
+	return &CardV2Builder{card: cardV2{
+		Schema: "2.0",
+		Body:   cardV2Body{Elements: make([]any, 0, 4)},
+	}}
+}
+
+func (cb *CardV2Builder) config() *cardV2Config {
+	if cb.card.Config == nil {
+		cb.card.Config = &cardV2Config{}
+	}
+	return cb.card.Config
+}
+
+func (cb *CardV2Builder) header() *cardV2Header {
+	if cb.card.Header == nil {
+		cb.card.Header = &cardV2Header{}
+	}
+	return cb.card.Header
+}
+
+// StreamingMode 是否开启流式更新模式
+//
+// https://open.feishu.cn/document/uAjLw4CM/ukzMukzMukzM/feishu-cards/card-json-v2-structure#3827dadd
+func (cb *CardV2Builder) StreamingMode(b bool) *CardV2Builder {
+	cb.config().StreamingMode = &b
+	return cb
+}
+
+// Summary 卡片的摘要信息，展示在会话列表中
+func (cb *CardV2Builder) Summary(content string) *CardV2Builder {
+	summary := cb.config().Summary
+	if summary == nil {
+		summary = &cardV2Text{}
+		cb.config().Summary = summary
+	}
+	summary.Content = content
+	return cb
+}
+
+// I18nSummary 指定语言的摘要信息
+func (cb *CardV2Builder) I18nSummary(language Language, content string) *CardV2Builder {
+	summary := cb.config().Summary
+	if summary == nil {
+		summary = &cardV2Text{}
+		cb.config().Summary = summary
+	}
+	summary.I18nContent = setI18nContent(summary.I18nContent, language, content)
+	return cb
+}
+
+// Locales 卡片生效的语言，未设置时所有语言均生效
+func (cb *CardV2Builder) Locales(languages ...Language) *CardV2Builder {
+	cb.config().Locales = languages
+	return cb
+}
+
+// EnableForward 是否允许转发卡片
+func (cb *CardV2Builder) EnableForward(b bool) *CardV2Builder {
+	cb.config().EnableForward = &b
+	return cb
+}
+
+// UpdateMulti 是否为共享卡片，2.0 卡片仅支持共享卡片
+func (cb *CardV2Builder) UpdateMulti(b bool) *CardV2Builder {
+	cb.config().UpdateMulti = &b
+	return cb
+}
+
+// WidthMode 卡片宽度模式
+func (cb *CardV2Builder) WidthMode(mode CardV2WidthMode) *CardV2Builder {
+	cb.config().WidthMode = mode
+	return cb
+}
+
+// CardLink 卡片整体的跳转链接，参考 CardGlobalConfig.CardLink
+func (cb *CardV2Builder) CardLink(defaultURL, pc, ios, android string) *CardV2Builder {
+	cb.card.CardLink = &cardLink{
+		URL:     defaultURL,
+		PC:      pc,
+		IOS:     ios,
+		Android: android,
+	}
+	return cb
+}
+
+// HeaderTitle 主标题
+//
+// https://open.feishu.cn/document/uAjLw4CM/ukzMukzMukzM/feishu-cards/card-json-v2-components/content-components/title
+func (cb *CardV2Builder) HeaderTitle(title string) *CardV2Builder {
+	h := cb.header()
+	if h.Title == nil {
+		h.Title = &cardV2Text{Tag: "plain_text"}
+	}
+	h.Title.Content = title
+	return cb
+}
+
+// HeaderI18nTitle 指定语言的主标题
+func (cb *CardV2Builder) HeaderI18nTitle(language Language, title string) *CardV2Builder {
+	h := cb.header()
+	if h.Title == nil {
+		h.Title = &cardV2Text{Tag: "plain_text"}
+	}
+	h.Title.I18nContent = setI18nContent(h.Title.I18nContent, language, title)
+	return cb
+}
+
+// HeaderSubtitle 副标题，不允许只配置副标题
+func (cb *CardV2Builder) HeaderSubtitle(subtitle string) *CardV2Builder {
+	h := cb.header()
+	if h.Subtitle == nil {
+		h.Subtitle = &cardV2Text{Tag: "plain_text"}
+	}
+	h.Subtitle.Content = subtitle
+	return cb
+}
+
+// HeaderI18nSubtitle 指定语言的副标题
+func (cb *CardV2Builder) HeaderI18nSubtitle(language Language, subtitle string) *CardV2Builder {
+	h := cb.header()
+	if h.Subtitle == nil {
+		h.Subtitle = &cardV2Text{Tag: "plain_text"}
+	}
+	h.Subtitle.I18nContent = setI18nContent(h.Subtitle.I18nContent, language, subtitle)
+	return cb
+}
+
+// HeaderTemplate 标题主题颜色
+func (cb *CardV2Builder) HeaderTemplate(template CardHeaderTemplate) *CardV2Builder {
+	cb.header().Template = template
+	return cb
+}
+
+// HeaderTextTags 标题后缀标签，最多展示 3 个
+func (cb *CardV2Builder) HeaderTextTags(tags []CardHeaderTextTag) *CardV2Builder {
+	h := cb.header()
+	h.TextTagList = append(h.TextTagList, newCardHeaderTextTags("", tags)...)
+	return cb
+}
+
+// HeaderI18nTextTags 指定语言的标题后缀标签
+func (cb *CardV2Builder) HeaderI18nTextTags(language Language, tags []CardHeaderTextTag) *CardV2Builder {
+	h := cb.header()
+	if h.I18nTextTagList == nil {
+		h.I18nTextTagList = make(map[Language][]cardHeaderI18nTextTag, 1)
+	}
+	h.I18nTextTagList[language] = append(h.I18nTextTagList[language], newCardHeaderTextTags(language, tags)...)
+	return cb
+}
+
+// HeaderIcon 标题的前缀图标（自定义图片）
+func (cb *CardV2Builder) HeaderIcon(imgKey string) *CardV2Builder {
+	cb.header().Icon = &cardV2HeaderIcon{Tag: "custom_icon", ImgKey: imgKey}
+	return cb
+}
+
+// HeaderStandardIcon 标题的前缀图标（图标库中的图标）
+//
+// 图标库: https://open.feishu.cn/document/uAjLw4CM/ukzMukzMukzM/feishu-cards/enumerations-for-icons
+func (cb *CardV2Builder) HeaderStandardIcon(token, color string) *CardV2Builder {
+	cb.header().Icon = &cardV2HeaderIcon{Tag: "standard_icon", Token: token, Color: color}
+	return cb
+}
+
+// HeaderPadding 标题组件的内边距，例如 12px 或 12px 8px
+func (cb *CardV2Builder) HeaderPadding(padding string) *CardV2Builder {
+	cb.header().Padding = padding
+	return cb
+}
+
+// Direction 正文中组件的排列方向，默认为 vertical
+func (cb *CardV2Builder) Direction(direction CardV2Direction) *CardV2Builder {
+	cb.card.Body.Direction = direction
+	return cb
+}
+
+// Padding 正文的内边距，例如 12px 或 12px 8px 12px 8px
+func (cb *CardV2Builder) Padding(padding string) *CardV2Builder {
+	cb.card.Body.Padding = padding
+	return cb
+}
+
+// HorizontalSpacing 正文中组件的水平间距，例如 small、medium、large 或 8px
+func (cb *CardV2Builder) HorizontalSpacing(spacing string) *CardV2Builder {
+	cb.card.Body.HorizontalSpacing = spacing
+	return cb
+}
+
+// VerticalSpacing 正文中组件的垂直间距，例如 small、medium、large 或 8px
+func (cb *CardV2Builder) VerticalSpacing(spacing string) *CardV2Builder {
+	cb.card.Body.VerticalSpacing = spacing
+	return cb
+}
+
+// Elements 正文中的组件，参考 CardBuilder.Elements
+func (cb *CardV2Builder) Elements(elements []CardElement) *CardV2Builder {
+	for i := range elements {
+		if elements[i] == nil {
+			continue
+		}
+		cb.card.Body.Elements = append(cb.card.Body.Elements, elements[i].Entity())
+	}
+	return cb
+}
+
+// ElementsJSON 正文中各组件的 JSON
+func (cb *CardV2Builder) ElementsJSON() ([]json.RawMessage, error) {
+	ret := make([]json.RawMessage, 0, len(cb.card.Body.Elements))
+	for i := range cb.card.Body.Elements {
+		raw, err := json.Marshal(cb.card.Body.Elements[i])
+		if err != nil {
+			return nil, fmt.Errorf("marshal element(%d): %w", i, err)
+		}
+		ret = append(ret, raw)
+	}
+	return ret, nil
+}
+
+func setI18nContent(m map[Language]string, language Language, content string) map[Language]string {
+	if m == nil {
+		m = make(map[Language]string, 1)
+	}
+	m[language] = content
+	return m
+}
+
+func newCardHeaderTextTags(language Language, tags []CardHeaderTextTag) []cardHeaderI18nTextTag {
+	ret := make([]cardHeaderI18nTextTag, 0, len(tags))
+	for _, tag := range tags {
+		ret = append(ret, cardHeaderI18nTextTag{
+			language: language,
+			Tag:      "text_tag",
+			Text:     cardHeaderComponentPlainText{Tag: "plain_text", Content: tag.Content},
+			Color:    tag.Color,
+		})
+	}
+	return ret
+}
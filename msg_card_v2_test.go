@@ -0,0 +1,75 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_cardMessageV2_Apply(t *testing.T) {
+	card := NewCardV2().
+		StreamingMode(false).
+		Summary("摘要").
+		I18nSummary(LanguageEnglish, "summary").
+		Locales(LanguageChinese, LanguageEnglish).
+		UpdateMulti(true).
+		WidthMode(CardV2WidthModeFill).
+		CardLink("https://open.feishu.cn", "", "", "").
+		HeaderTitle("主标题").
+		HeaderI18nTitle(LanguageEnglish, "title").
+		HeaderSubtitle("副标题").
+		HeaderTemplate(CardHeaderTemplateBlue).
+		HeaderTextTags([]CardHeaderTextTag{{Content: "标签", Color: CardHeaderTextTagColorRed}}).
+		HeaderI18nTextTags(LanguageEnglish, []CardHeaderTextTag{{Content: "tag"}}).
+		HeaderIcon("img_xxx").
+		HeaderPadding("12px").
+		Direction(CardV2DirectionHorizontal).
+		Padding("12px 8px").
+		VerticalSpacing("small").
+		Elements([]CardElement{
+			NewCardElementMarkdown("**加粗**"),
+			nil,
+			NewCardElementHorizontalRule(),
+		})
+
+	want := `{"msg_type":"interactive","card":{"schema":"2.0",` +
+		`"config":{"summary":{"content":"摘要","i18n_content":{"en_us":"summary"}},"streaming_mode":false,"locales":["zh_cn","en_us"],"update_multi":true,"width_mode":"fill"},` +
+		`"card_link":{"url":"https://open.feishu.cn"},` +
+		`"header":{"title":{"tag":"plain_text","content":"主标题","i18n_content":{"en_us":"title"}},` +
+		`"subtitle":{"tag":"plain_text","content":"副标题"},` +
+		`"text_tag_list":[{"tag":"text_tag","text":{"tag":"plain_text","content":"标签"},"color":"red"}],` +
+		`"i18n_text_tag_list":{"en_us":[{"tag":"text_tag","text":{"tag":"plain_text","content":"tag"}}]},` +
+		`"template":"blue","icon":{"tag":"custom_icon","img_key":"img_xxx"},"padding":"12px"},` +
+		`"body":{"direction":"horizontal","padding":"12px 8px","vertical_spacing":"small","elements":[{"tag":"markdown","content":"**加粗**"},{"tag":"hr"}]}}}`
+
+	if got := applyJSON(t, cardMessageV2{card: card}); !jsonEqual(got, []byte(want)) {
+		t.Errorf("Actual:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func Test_cardMessageV2_Apply_unsupported(t *testing.T) {
+	tests := []struct {
+		name     string
+		elements []CardElement
+	}{
+		{
+			name:     "note",
+			elements: []CardElement{NewCardElementNote().AddElementWithPlainText("备注")},
+		},
+		{
+			name: "column_set_action",
+			elements: []CardElement{NewCardElementColumnSet().Columns([]*CardElementColumnSetColumn{
+				NewCardElementColumnSetColumn().Elements([]CardElement{NewCardElementAction()}),
+			})},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var body MessageBody
+			err := cardMessageV2{card: NewCardV2().Elements(tt.elements)}.Apply(&body)
+			if !errors.Is(err, ErrCardV2UnsupportedElement) {
+				t.Errorf("Actual error: %v, want: %v", err, ErrCardV2UnsupportedElement)
+			}
+		})
+	}
+}
@@ -0,0 +1,474 @@
+package feishu_bot_api
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrUnsupportedMsgType 不支持的消息类型
+var ErrUnsupportedMsgType = errors.New("unsupported msg_type")
+
+// ParseMessageBody 将 MessageBody 的 JSON 还原为对应的消息，可以通过 AsText、AsRichText、AsCard 等查看、修改后重新发送
+//
+// 还原后的消息再次 Apply 得到的 JSON 与原始 JSON 语义一致；无法还原为构建器的消息（例如包含构建器不支持的字段）
+// 返回原始的 MessageBody，同样可以直接发送。卡片中已知的元素还原为对应的构建器（参考 CardBuilder.CardElements），未知的元素以原始 JSON 保留
+func ParseMessageBody(data []byte) (Message, error) {
+	var body MessageBody
+	if err := json.Unmarshal(data, &body); err != nil {
+		return nil, fmt.Errorf("parse message body: %w", err)
+	}
+
+	msg, err := parseMessage(body)
+	if err != nil {
+		return nil, fmt.Errorf("parse message body(%s): %w", body.MsgType, err)
+	}
+
+	if ok, err := sameMessageBody(msg, body); err != nil {
+		return nil, fmt.Errorf("parse message body(%s): %w", body.MsgType, err)
+	} else if !ok {
+		return body, nil
+	}
+	return msg, nil
+}
+
+func parseMessage(body MessageBody) (Message, error) {
+	switch body.MsgType {
+	case "text":
+		if body.Content == nil {
+			return nil, errors.New("missing content")
+		}
+		return textMessage(body.Content.Text), nil
+	case "image":
+		if body.Content == nil {
+			return nil, errors.New("missing content")
+		}
+		return imageMessage(body.Content.ImageKey), nil
+	case "share_chat":
+		if body.Content == nil {
+			return nil, errors.New("missing content")
+		}
+		return groupBusinessCard(body.Content.ShareChatID), nil
+	case "post":
+		if body.Content == nil || body.Content.Post == nil {
+			return nil, errors.New("missing content.post")
+		}
+		return parseRichTextMessage(*body.Content.Post)
+	case "interactive":
+		if body.Card == nil {
+			return nil, errors.New("missing card")
+		}
+		return parseCardMessage(*body.Card)
+	case "":
+		return nil, errors.New("missing msg_type")
+	default:
+		return nil, ErrUnsupportedMsgType
+	}
+}
+
+func parseRichTextMessage(raw json.RawMessage) (Message, error) {
+	languages, err := objectKeys(raw)
+	if err != nil {
+		return nil, fmt.Errorf("post: %w", err)
+	}
+
+	var bodies map[string]richTextBody
+	if err := json.Unmarshal(raw, &bodies); err != nil {
+		return nil, fmt.Errorf("post: %w", err)
+	}
+
+	m := make(richTextMessage, 0, len(languages))
+	for _, language := range languages {
+		m = append(m, &RichTextBuilder{language: Language(language), body: bodies[language]})
+	}
+	return m, nil
+}
+
+func parseCardMessage(raw json.RawMessage) (Message, error) {
+	var probe struct {
+		Type   string `json:"type"`
+		Schema string `json:"schema"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return nil, fmt.Errorf("card: %w", err)
+	}
+	if probe.Type == "template" {
+		var tpl MessageBodyCardTemplate
+		if err := json.Unmarshal(raw, &tpl); err != nil {
+			return nil, fmt.Errorf("card(template): %w", err)
+		}
+		var variables any
+		if tpl.Data.TemplateVariable != nil {
+			variables = *tpl.Data.TemplateVariable
+		}
+		return cardMessageViaTemplate{id: tpl.Data.TemplateID, variables: variables}, nil
+	}
+	if probe.Schema == "2.0" {
+		return parseCardMessageV2(raw)
+	}
+
+	var card struct {
+		Header struct {
+			Title struct {
+				Content string          `json:"content"`
+				I18n    json.RawMessage `json:"i18n"`
+			} `json:"title"`
+			Subtitle *struct {
+				Content string            `json:"content"`
+				I18n    map[string]string `json:"i18n"`
+			} `json:"subtitle"`
+			Icon            *cardHeaderIcon                    `json:"icon"`
+			Template        CardHeaderTemplate                 `json:"template"`
+			TextTagList     []cardHeaderTextTagJSON            `json:"text_tag_list"`
+			I18nTextTagList map[string][]cardHeaderTextTagJSON `json:"i18n_text_tag_list"`
+		} `json:"header"`
+		Elements     []json.RawMessage            `json:"elements"`
+		I18nElements map[string][]json.RawMessage `json:"i18n_elements"`
+		Config       *cardConfig                  `json:"config"`
+		CardLink     *cardLink                    `json:"card_link"`
+	}
+	if err := json.Unmarshal(raw, &card); err != nil {
+		return nil, fmt.Errorf("card: %w", err)
+	}
+
+	m := cardMessage{
+		globalConf: &CardGlobalConfig{
+			headerIcon:     card.Header.Icon,
+			headerTemplate: card.Header.Template,
+			config:         card.Config,
+			link:           card.CardLink,
+		},
+	}
+
+	hasI18nTitle := len(card.Header.Title.I18n) > 0 && string(card.Header.Title.I18n) != "null"
+	if !hasI18nTitle && card.I18nElements == nil {
+		// 非多语言的卡片结构：header.title.content、elements
+		cb := NewCard("", card.Header.Title.Content)
+		if card.Header.Subtitle != nil {
+			cb.HeaderSubtitle(card.Header.Subtitle.Content)
+		}
+		if card.Header.TextTagList != nil {
+			cb.HeaderTextTags(textTagsFromJSON(card.Header.TextTagList))
+		}
+		cb.elements = parseCardElements(card.Elements)
+		m.builders = []*CardBuilder{cb}
+		m.plain = true
+		return m, nil
+	}
+
+	var (
+		languages []string
+		titles    map[string]string
+	)
+	if hasI18nTitle {
+		var err error
+		if languages, err = objectKeys(card.Header.Title.I18n); err != nil {
+			return nil, fmt.Errorf("card: header.title.i18n: %w", err)
+		}
+		if err := json.Unmarshal(card.Header.Title.I18n, &titles); err != nil {
+			return nil, fmt.Errorf("card: header.title.i18n: %w", err)
+		}
+	}
+
+	m.builders = make([]*CardBuilder, 0, len(languages))
+	for _, language := range languages {
+		cb := NewCard(Language(language), titles[language])
+		if card.Header.Subtitle != nil {
+			cb.HeaderSubtitle(card.Header.Subtitle.I18n[language])
+		}
+		if tags, ok := card.Header.I18nTextTagList[language]; ok {
+			cb.HeaderTextTags(textTagsFromJSON(tags))
+		}
+		cb.elements = append(cb.elements, parseCardElements(card.I18nElements[language])...)
+		m.builders = append(m.builders, cb)
+	}
+	return m, nil
+}
+
+type cardHeaderTextTagJSON struct {
+	Text struct {
+		Content string `json:"content"`
+	} `json:"text"`
+	Color CardHeaderTextTagColor `json:"color"`
+}
+
+func textTagsFromJSON(tags []cardHeaderTextTagJSON) []CardHeaderTextTag {
+	ret := make([]CardHeaderTextTag, len(tags))
+	for i := range tags {
+		ret[i] = CardHeaderTextTag{Content: tags[i].Text.Content, Color: tags[i].Color}
+	}
+	return ret
+}
+
+// parseCardElements 还原卡片元素，raws 为 nil 时返回 nil
+func parseCardElements(raws []json.RawMessage) []any {
+	if raws == nil {
+		return nil
+	}
+	ret := make([]any, 0, len(raws))
+	for _, raw := range raws {
+		ret = append(ret, parseCardElement(raw))
+	}
+	return ret
+}
+
+// parseCardElement 将已知 tag 的元素还原为对应的实体（与 CardElement.Entity 一致）
+//
+// 未知的 tag，或者还原后的 JSON 与原始 JSON 不一致（例如包含不支持的字段）时，保留原始 JSON
+func parseCardElement(raw json.RawMessage) any {
+	var probe struct {
+		Tag string `json:"tag"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return raw
+	}
+
+	var (
+		entity any
+		err    error
+	)
+	switch probe.Tag {
+	case "markdown":
+		entity, err = decodeCardElement[cardElementMarkdown](raw)
+	case "div":
+		entity, err = decodeCardElement[cardElementDiv](raw)
+	case "hr":
+		entity, err = decodeCardElement[cardElementHorizontalRule](raw)
+	case "img":
+		entity, err = decodeCardElement[cardElementImage](raw)
+	case "note":
+		entity, err = decodeCardElement[cardElementNote](raw)
+	case "action":
+		entity, err = decodeCardElement[cardElementAction](raw)
+	case "column_set":
+		entity, err = decodeCardElementColumnSet(raw)
+	default:
+		return raw
+	}
+	if err != nil {
+		return raw
+	}
+
+	if got, err := json.Marshal(entity); err != nil || !jsonEqual(got, raw) {
+		return raw
+	}
+	return entity
+}
+
+func decodeCardElement[T any](raw json.RawMessage) (T, error) {
+	var e T
+	err := json.Unmarshal(raw, &e)
+	return e, err
+}
+
+// decodeCardElementColumnSet 列中的元素同样按 parseCardElement 还原
+func decodeCardElementColumnSet(raw json.RawMessage) (cardElementColumnSet, error) {
+	cs, err := decodeCardElement[cardElementColumnSet](raw)
+	if err != nil {
+		return cs, err
+	}
+
+	var columns struct {
+		Columns []struct {
+			Elements []json.RawMessage `json:"elements"`
+		} `json:"columns"`
+	}
+	if err := json.Unmarshal(raw, &columns); err != nil {
+		return cs, err
+	}
+	for i := range cs.Columns {
+		if i < len(columns.Columns) {
+			cs.Columns[i].Elements = parseCardElements(columns.Columns[i].Elements)
+		}
+	}
+	return cs, nil
+}
+
+func parseCardMessageV2(raw json.RawMessage) (Message, error) {
+	cb := &CardV2Builder{}
+	if err := json.Unmarshal(raw, &cb.card); err != nil {
+		return nil, fmt.Errorf("card(2.0): %w", err)
+	}
+
+	var body struct {
+		Body struct {
+			Elements []json.RawMessage `json:"elements"`
+		} `json:"body"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, fmt.Errorf("card(2.0): %w", err)
+	}
+	cb.card.Body.Elements = nil
+	for _, e := range body.Body.Elements {
+		cb.card.Body.Elements = append(cb.card.Body.Elements, e)
+	}
+	return cardMessageV2{card: cb}, nil
+}
+
+// sameMessageBody msg 经过 Apply 后与 body 的 JSON 语义是否一致
+func sameMessageBody(msg Message, body MessageBody) (bool, error) {
+	var applied MessageBody
+	if err := msg.Apply(&applied); err != nil {
+		// 例如富文本标签未通过校验，保留原始的 MessageBody
+		return false, nil
+	}
+
+	want, err := json.Marshal(body)
+	if err != nil {
+		return false, err
+	}
+	got, err := json.Marshal(applied)
+	if err != nil {
+		return false, err
+	}
+	return jsonEqual(want, got), nil
+}
+
+func jsonEqual(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return false
+	}
+	return reflect.DeepEqual(va, vb)
+}
+
+// objectKeys 按出现顺序返回 JSON 对象的 key
+func objectKeys(raw json.RawMessage) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if tok, err := dec.Token(); err != nil {
+		return nil, err
+	} else if tok != json.Delim('{') {
+		return nil, fmt.Errorf("expected object, got %v", tok)
+	}
+
+	var keys []string
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return nil, err
+		}
+		keys = append(keys, tok.(string))
+	}
+	return keys, nil
+}
+
+// --------------------------------------------------------------------------------
+
+// AsText 文本消息的内容
+func AsText(msg Message) (string, bool) {
+	m, ok := msg.(textMessage)
+	return string(m), ok
+}
+
+// AsImage 图片消息的 image_key
+func AsImage(msg Message) (string, bool) {
+	m, ok := msg.(imageMessage)
+	return string(m), ok
+}
+
+// AsGroupBusinessCard 群名片消息的 chat_id
+func AsGroupBusinessCard(msg Message) (string, bool) {
+	m, ok := msg.(groupBusinessCard)
+	return string(m), ok
+}
+
+// AsRichText 富文本消息各语言的构建器，修改构建器会直接影响 msg
+func AsRichText(msg Message) ([]*RichTextBuilder, bool) {
+	m, ok := msg.(richTextMessage)
+	return m, ok
+}
+
+// AsCard 卡片消息的全局配置与各语言的构建器，修改构建器会直接影响 msg
+func AsCard(msg Message) (*CardGlobalConfig, []*CardBuilder, bool) {
+	m, ok := msg.(cardMessage)
+	return m.globalConf, m.builders, ok
+}
+
+// AsCardV2 卡片 JSON 2.0 消息的构建器，修改构建器会直接影响 msg
+func AsCardV2(msg Message) (*CardV2Builder, bool) {
+	m, ok := msg.(cardMessageV2)
+	return m.card, ok
+}
+
+// AsCardTemplate 模板卡片消息的模板 ID 与变量
+func AsCardTemplate(msg Message) (id string, variables any, ok bool) {
+	m, ok := msg.(cardMessageViaTemplate)
+	return m.id, m.variables, ok
+}
+
+// --------------------------------------------------------------------------------
+
+// Language 语言
+func (rtb *RichTextBuilder) Language() Language { return rtb.language }
+
+// Title 标题
+func (rtb *RichTextBuilder) Title() string { return rtb.body.Title }
+
+// Language 语言
+func (cb *CardBuilder) Language() Language { return cb.language }
+
+// Title 标题
+func (cb *CardBuilder) Title() string { return cb.headerTitleContent }
+
+// Subtitle 副标题
+func (cb *CardBuilder) Subtitle() string { return cb.headerSubtitleContent }
+
+// ElementsJSON 卡片正文中各元素的 JSON
+func (cb *CardBuilder) ElementsJSON() ([]json.RawMessage, error) {
+	ret := make([]json.RawMessage, 0, len(cb.elements))
+	for i := range cb.elements {
+		raw, err := json.Marshal(cb.elements[i])
+		if err != nil {
+			return nil, fmt.Errorf("marshal element(%d): %w", i, err)
+		}
+		ret = append(ret, raw)
+	}
+	return ret, nil
+}
+
+// CardElements 卡片正文中的各元素：已知的元素还原为对应的构建器（例如 *CardElementMarkdown），未知的元素为 CardElementRaw
+//
+// 返回的构建器为副本，修改后可以通过 SetElements 写回
+func (cb *CardBuilder) CardElements() []CardElement {
+	ret := make([]CardElement, 0, len(cb.elements))
+	for _, entity := range cb.elements {
+		ret = append(ret, cardElementOf(entity))
+	}
+	return ret
+}
+
+// SetElements 替换卡片的正文内容，参考 CardBuilder.Elements
+func (cb *CardBuilder) SetElements(elements []CardElement) *CardBuilder {
+	cb.elements = make([]any, 0, len(elements))
+	return cb.Elements(elements)
+}
+
+func cardElementOf(entity any) CardElement {
+	switch e := entity.(type) {
+	case cardElementMarkdown:
+		return &CardElementMarkdown{md: e}
+	case cardElementDiv:
+		return &CardElementDiv{div: e}
+	case cardElementHorizontalRule:
+		return &CardElementHorizontalRule{hr: e}
+	case cardElementImage:
+		return &CardElementImage{img: e}
+	case cardElementNote:
+		return &CardElementNote{note: e}
+	case cardElementAction:
+		return &CardElementAction{action: e}
+	case cardElementColumnSet:
+		return &CardElementColumnSet{cs: e}
+	case json.RawMessage:
+		return CardElementRaw(e)
+	default:
+		raw, _ := json.Marshal(e)
+		return CardElementRaw(raw)
+	}
+}
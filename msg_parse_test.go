@@ -0,0 +1,303 @@
+package feishu_bot_api
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func applyJSON(t *testing.T, msg Message) []byte {
+	t.Helper()
+
+	var body MessageBody
+	requireNoError(t, msg.Apply(&body))
+	raw, err := json.Marshal(body)
+	requireNoError(t, err)
+	return raw
+}
+
+func TestParseMessageBody(t *testing.T) {
+	tests := []struct {
+		name    string
+		msg     Message
+		wantRaw bool
+	}{
+		{
+			name: "text",
+			msg:  textMessage("hi " + TextAtEveryone()),
+		},
+		{
+			name: "image",
+			msg:  imageMessage("img_7ea74629-9191-4176-998c-2e603c9c5e8g"),
+		},
+		{
+			name: "share_chat",
+			msg:  groupBusinessCard("oc_f5b1a7eb27ae2c7b6adc2a74faf339ff"),
+		},
+		{
+			name: "post",
+			msg: richTextMessage{
+				NewRichText(LanguageChinese, "标题").Text("内容", false).Hyperlink("链接", "https://open.feishu.cn").At("ou_xxx", "Tom"),
+				NewRichText(LanguageEnglish, "title").Text("content", true).Image("img_xxx"),
+			},
+		},
+		{
+			name: "post_tags",
+			msg: richTextMessage{
+				NewRichText(LanguageChinese, "告警").
+					TextWithStyle("api", false, RichTextStyleBold).
+					Emotion("SMILE").
+					CodeBlock("GO", "panic: oops").
+					HorizontalRule().
+					Markdown("**md**").
+					Media("file_xxx", "img_xxx"),
+			},
+		},
+		{
+			name: "interactive",
+			msg: cardMessage{
+				globalConf: NewCardGlobalConfig().
+					HeaderIcon("img_xxx").
+					HeaderTemplate(CardHeaderTemplateBlue).
+					ConfigEnableForward(true).
+					CardLink("https://open.feishu.cn", "", "", ""),
+				builders: []*CardBuilder{
+					NewCard(LanguageChinese, "主标题").
+						HeaderSubtitle("副标题").
+						HeaderTextTags([]CardHeaderTextTag{{Content: "标签", Color: CardHeaderTextTagColorRed}}).
+						Elements([]CardElement{
+							NewCardElementMarkdown("**加粗**"),
+							NewCardElementHorizontalRule(),
+							NewCardElementNote().AddElementWithPlainText("备注"),
+						}),
+					NewCard(LanguageEnglish, "title").
+						HeaderSubtitle("subtitle").
+						Elements([]CardElement{NewCardElementMarkdown("**bold**")}),
+				},
+			},
+		},
+		{
+			name: "interactive_plain",
+			msg: cardMessage{
+				plain:      true,
+				globalConf: NewCardGlobalConfig().HeaderTemplate(CardHeaderTemplateRed),
+				builders: []*CardBuilder{
+					NewCard("", "告警").
+						HeaderSubtitle("api").
+						HeaderTextTags([]CardHeaderTextTag{{Content: "P0", Color: CardHeaderTextTagColorRed}}).
+						Elements([]CardElement{
+							NewCardElementMarkdown("**内容**").TextAlign(CardElementMarkdownTextAlignCenter),
+							NewCardElementImage("img_xxx", "图片"),
+						}),
+				},
+			},
+		},
+		{
+			name: "interactive_plain_json",
+			msg: MessageBody{
+				MsgType: "interactive",
+				Card: rawMessage(`{"header":{"title":{"tag":"plain_text","content":"标题"},"template":"blue"},` +
+					`"elements":[{"tag":"div","text":{"tag":"lark_md","content":"**x**"}},{"tag":"hr"},{"tag":"chart","chart_spec":{}}]}`),
+			},
+		},
+		{
+			name: "interactive_template",
+			msg:  cardMessageViaTemplate{id: "AAqk1xxxxxx", variables: map[string]any{"name": "Tom", "n": 1}},
+		},
+		{
+			name: "interactive_v2",
+			msg: cardMessageV2{card: NewCardV2().
+				StreamingMode(true).
+				Summary("摘要").
+				Locales(LanguageChinese, LanguageEnglish).
+				HeaderTitle("主标题").
+				HeaderI18nTitle(LanguageEnglish, "title").
+				HeaderTextTags([]CardHeaderTextTag{{Content: "标签", Color: CardHeaderTextTagColorRed}}).
+				HeaderStandardIcon("alarm_outlined", "orange").
+				Direction(CardV2DirectionVertical).
+				Elements([]CardElement{NewCardElementMarkdown("**加粗**"), NewCardElementHorizontalRule()}),
+			},
+		},
+		{
+			name: "interactive_v2_unknown_field",
+			msg: MessageBody{
+				MsgType: "interactive",
+				Card:    rawMessage(`{"schema":"2.0","body":{"elements":[{"tag":"chart","chart_spec":{"type":"line"}}]},"behaviors":[]}`),
+			},
+			wantRaw: true,
+		},
+		{
+			name: "interactive_unknown_element",
+			msg: MessageBody{
+				MsgType: "interactive",
+				Card:    rawMessage(`{"header":{"title":{"tag":"plain_text","i18n":{"zh_cn":"标题"}}},"i18n_elements":{"zh_cn":[{"tag":"chart","chart_spec":{"type":"line"}}]}}`),
+			},
+		},
+		{
+			name: "post_invalid_label",
+			msg: MessageBody{
+				MsgType: "post",
+				Content: &MessageBodyContent{
+					Post: rawMessage(`{"zh_cn":{"title":"标题","content":[[{"tag":"img","image_key":"img_xxx","style":["bold"]}]]}}`),
+				},
+			},
+			wantRaw: true,
+		},
+		{
+			name: "post_unknown_field",
+			msg: MessageBody{
+				MsgType: "post",
+				Content: &MessageBodyContent{
+					Post: rawMessage(`{"zh_cn":{"title":"标题","content":[[{"tag":"text","text":"x","unknown":true}]]}}`),
+				},
+			},
+			wantRaw: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			want := applyJSON(t, tt.msg)
+
+			msg, err := ParseMessageBody(want)
+			requireNoError(t, err)
+
+			if _, isRaw := msg.(MessageBody); isRaw != tt.wantRaw {
+				t.Fatalf("Actual raw MessageBody: %t (%T), want: %t", isRaw, msg, tt.wantRaw)
+			}
+
+			if got := applyJSON(t, msg); !jsonEqual(got, want) {
+				t.Errorf("Actual:\n%s\nwant:\n%s", got, want)
+			}
+		})
+	}
+}
+
+func TestParseMessageBody_error(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{name: "invalid_json", data: `{`},
+		{name: "missing_msg_type", data: `{}`},
+		{name: "missing_content", data: `{"msg_type":"text"}`},
+		{name: "missing_card", data: `{"msg_type":"interactive"}`},
+		{name: "unsupported", data: `{"msg_type":"audio","content":{}}`, wantErr: ErrUnsupportedMsgType},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMessageBody([]byte(tt.data))
+			if err == nil {
+				t.Fatal("Actual err is nil, want not nil")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("Actual err: %v, want: %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseMessageBody_modify(t *testing.T) {
+	data := applyJSON(t, cardMessage{
+		globalConf: NewCardGlobalConfig().HeaderTemplate(CardHeaderTemplateGreen),
+		builders: []*CardBuilder{
+			NewCard(LanguageChinese, "标题").Elements([]CardElement{NewCardElementMarkdown("内容")}),
+		},
+	})
+
+	msg, err := ParseMessageBody(data)
+	requireNoError(t, err)
+
+	_, builders, ok := AsCard(msg)
+	if !ok {
+		t.Fatalf("Actual %T, want cardMessage", msg)
+	}
+	if len(builders) != 1 || builders[0].Language() != LanguageChinese || builders[0].Title() != "标题" {
+		t.Fatalf("Actual builders: %+v", builders)
+	}
+	builders[0].Elements([]CardElement{NewCardElementHorizontalRule(), NewCardElementMarkdown("footer")})
+
+	want := applyJSON(t, cardMessage{
+		globalConf: NewCardGlobalConfig().HeaderTemplate(CardHeaderTemplateGreen),
+		builders: []*CardBuilder{
+			NewCard(LanguageChinese, "标题").Elements([]CardElement{
+				NewCardElementMarkdown("内容"),
+				NewCardElementHorizontalRule(),
+				NewCardElementMarkdown("footer"),
+			}),
+		},
+	})
+	if got := applyJSON(t, msg); !jsonEqual(got, want) {
+		t.Errorf("Actual:\n%s\nwant:\n%s", got, want)
+	}
+
+	es, err := builders[0].ElementsJSON()
+	requireNoError(t, err)
+	if len(es) != 3 {
+		t.Errorf("Actual elements: %d, want: 3", len(es))
+	}
+}
+
+func TestParseMessageBody_cardElements(t *testing.T) {
+	data := applyJSON(t, MessageBody{
+		MsgType: "interactive",
+		Card: rawMessage(`{"header":{"title":{"tag":"plain_text","content":"标题"}},"elements":[` +
+			`{"tag":"markdown","content":"内容"},` +
+			`{"tag":"column_set","flex_mode":"none","columns":[{"tag":"column","elements":[{"tag":"markdown","content":"列"},{"tag":"chart","chart_spec":{}}]}]},` +
+			`{"tag":"markdown","content":"x","unknown":true},` +
+			`{"tag":"chart","chart_spec":{}}]}`),
+	})
+
+	msg, err := ParseMessageBody(data)
+	requireNoError(t, err)
+	_, builders, ok := AsCard(msg)
+	if !ok || len(builders) != 1 || builders[0].Language() != "" {
+		t.Fatalf("Actual %T, want a non-i18n cardMessage", msg)
+	}
+
+	elements := builders[0].CardElements()
+	if len(elements) != 4 {
+		t.Fatalf("Actual elements: %d, want: 4", len(elements))
+	}
+	md, ok := elements[0].(*CardElementMarkdown)
+	if !ok {
+		t.Fatalf("Actual element(0): %T, want: %T", elements[0], md)
+	}
+	cs, ok := elements[1].(*CardElementColumnSet)
+	if !ok {
+		t.Fatalf("Actual element(1): %T, want: %T", elements[1], cs)
+	}
+	if es := cs.cs.Columns[0].Elements; len(es) != 2 {
+		t.Errorf("Actual column elements: %d, want: 2", len(es))
+	} else if _, ok := es[0].(cardElementMarkdown); !ok {
+		t.Errorf("Actual column element(0): %T, want: %T", es[0], cardElementMarkdown{})
+	} else if _, ok := es[1].(json.RawMessage); !ok {
+		t.Errorf("Actual column element(1): %T, want: %T", es[1], json.RawMessage{})
+	}
+	for _, i := range []int{2, 3} {
+		if _, ok := elements[i].(CardElementRaw); !ok {
+			t.Errorf("Actual element(%d): %T, want: %T", i, elements[i], CardElementRaw{})
+		}
+	}
+
+	md.Content("修改后")
+	builders[0].SetElements(elements)
+
+	want := `{"header":{"title":{"tag":"plain_text","content":"标题"}},"elements":[` +
+		`{"tag":"markdown","content":"修改后"},` +
+		`{"tag":"column_set","flex_mode":"none","columns":[{"tag":"column","elements":[{"tag":"markdown","content":"列"},{"tag":"chart","chart_spec":{}}]}]},` +
+		`{"tag":"markdown","content":"x","unknown":true},` +
+		`{"tag":"chart","chart_spec":{}}]}`
+	var body MessageBody
+	requireNoError(t, msg.Apply(&body))
+	if got := []byte(*body.Card); !jsonEqual(got, []byte(want)) {
+		t.Errorf("Actual:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func rawMessage(s string) *json.RawMessage {
+	raw := json.RawMessage(s)
+	return &raw
+}
@@ -3,7 +3,10 @@
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 )
 
 var _ Message = (*richTextMessage)(nil)
@@ -45,7 +48,12 @@ func (m richTextMessage) marshal() (json.RawMessage, error) {
 			continue
 		}
 
-		bs, err := json.Marshal(rtb.body)
+		body := rtb.body.trimEmptyParagraphs()
+		if err := body.validate(); err != nil {
+			return nil, fmt.Errorf("validate(%s): %w", rtb.language, err)
+		}
+
+		bs, err := json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("marshal(%s): %w", rtb.language, err)
 		}
@@ -84,6 +92,9 @@ func (m richTextMessage) marshal() (json.RawMessage, error) {
 		// 仅 文本标签(text) 使用；表示是否 unescape 解码。默认值为 false，未用到 unescape 时可以不填
 		UnEscape *bool `json:"un_escape,omitempty"`
 
+		// 仅 文本标签(text)、超链接标签(a)、@ 标签(at) 使用；文本样式
+		Style []RichTextStyle `json:"style,omitempty"`
+
 		// 仅 超链接标签(a) 使用；链接地址，需要确保链接地址的合法性，否则消息会发送失败
 		Href string `json:"href,omitempty"`
 
@@ -95,9 +106,32 @@ func (m richTextMessage) marshal() (json.RawMessage, error) {
 		// 仅 图片标签(img) 使用；图片的唯一标识。可通过 上传图片 接口获取 image_key
 		// https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/image/create
 		ImageKey string `json:"image_key,omitempty"`
+
+		// 仅 视频标签(media) 使用；视频文件的唯一标识。可通过 上传文件 接口获取 file_key
+		// https://open.feishu.cn/document/server-docs/im-v1/file/create
+		FileKey string `json:"file_key,omitempty"`
+
+		// 仅 表情标签(emotion) 使用；表情类型
+		// https://open.feishu.cn/document/server-docs/im-v1/message-reaction/emojis-introduce
+		EmojiType string `json:"emoji_type,omitempty"`
+
+		// 仅 代码块标签(code_block) 使用；代码块的语言类型，例如 GO、PYTHON、JSON
+		Language string `json:"language,omitempty"`
 	}
 )
 
+// RichTextStyle 文本样式
+//
+// https://open.feishu.cn/document/server-docs/im-v1/message-content-description/create_json#45e0953e
+type RichTextStyle string
+
+const (
+	RichTextStyleBold        RichTextStyle = "bold"
+	RichTextStyleItalic      RichTextStyle = "italic"
+	RichTextStyleUnderline   RichTextStyle = "underline"
+	RichTextStyleLineThrough RichTextStyle = "lineThrough"
+)
+
 func NewRichText(language Language, title string) *RichTextBuilder {
 	rtb := &RichTextBuilder{
 		language: language,
@@ -113,27 +147,13 @@ func NewRichText(language Language, title string) *RichTextBuilder {
 //
 // unEscape 表示是否 unescape 解码，未用到 unescape 时传入 false
 func (rtb *RichTextBuilder) Text(text string, unEscape bool) *RichTextBuilder {
-	lbl := richTextLabel{
-		Tag:      "text",
-		Text:     text,
-		UnEscape: &unEscape,
-	}
-	paragraph := rtb.lastParagraph()
-	paragraph = append(paragraph, lbl)
-	rtb.updateLastParagraph(paragraph)
+	rtb.appendLabel(newRichTextLabelText(text, unEscape))
 	return rtb
 }
 
 // Hyperlink 超链接标签
 func (rtb *RichTextBuilder) Hyperlink(text, href string) *RichTextBuilder {
-	lbl := richTextLabel{
-		Tag:  "a",
-		Text: text,
-		Href: href,
-	}
-	paragraph := rtb.lastParagraph()
-	paragraph = append(paragraph, lbl)
-	rtb.updateLastParagraph(paragraph)
+	rtb.appendLabel(newRichTextLabelHyperlink(text, href))
 	return rtb
 }
 
@@ -144,25 +164,12 @@ func (rtb *RichTextBuilder) Hyperlink(text, href string) *RichTextBuilder {
 //	@ 单个用户时，id 字段必须是有效值（仅支持 @ 自定义机器人所在群的群成员）
 //	@ 所有人时，填 all (也可以使用 RichTextBuilder.AtEveryone)
 func (rtb *RichTextBuilder) At(id, name string) *RichTextBuilder {
-	lbl := richTextLabel{
-		Tag:      "at",
-		UserID:   id,
-		UserName: name,
-	}
-	paragraph := rtb.lastParagraph()
-	paragraph = append(paragraph, lbl)
-	rtb.updateLastParagraph(paragraph)
+	rtb.appendLabel(newRichTextLabelAt(id, name))
 	return rtb
 }
 
 func (rtb *RichTextBuilder) AtEveryone() *RichTextBuilder {
-	lbl := richTextLabel{
-		Tag:    "at",
-		UserID: "all",
-	}
-	paragraph := rtb.lastParagraph()
-	paragraph = append(paragraph, lbl)
-	rtb.updateLastParagraph(paragraph)
+	rtb.appendLabel(newRichTextLabelAt("all", ""))
 	return rtb
 }
 
@@ -171,14 +178,123 @@ func (rtb *RichTextBuilder) AtEveryone() *RichTextBuilder {
 // 图片的唯一标识。可通过 上传图片 接口获取
 // https://open.feishu.cn/document/uAjLw4CM/ukTMukTMukTM/reference/im-v1/image/create
 func (rtb *RichTextBuilder) Image(imgKey string) *RichTextBuilder {
-	lbl := richTextLabel{
-		Tag:      "img",
-		ImageKey: imgKey,
+	rtb.appendLabel(newRichTextLabelImage(imgKey))
+	return rtb
+}
+
+// TextWithStyle 带样式的文本标签
+func (rtb *RichTextBuilder) TextWithStyle(text string, unEscape bool, styles ...RichTextStyle) *RichTextBuilder {
+	lbl := newRichTextLabelText(text, unEscape)
+	lbl.Style = styles
+	rtb.appendLabel(lbl)
+	return rtb
+}
+
+// HyperlinkWithStyle 带样式的超链接标签
+func (rtb *RichTextBuilder) HyperlinkWithStyle(text, href string, styles ...RichTextStyle) *RichTextBuilder {
+	lbl := newRichTextLabelHyperlink(text, href)
+	lbl.Style = styles
+	rtb.appendLabel(lbl)
+	return rtb
+}
+
+// AtWithStyle 带样式的 @ 标签
+func (rtb *RichTextBuilder) AtWithStyle(id, name string, styles ...RichTextStyle) *RichTextBuilder {
+	lbl := newRichTextLabelAt(id, name)
+	lbl.Style = styles
+	rtb.appendLabel(lbl)
+	return rtb
+}
+
+// Media 视频标签
+//
+// fileKey: 视频文件的唯一标识，可通过 上传文件 接口获取
+// imgKey: 视频封面图片的唯一标识，可选
+func (rtb *RichTextBuilder) Media(fileKey, imgKey string) *RichTextBuilder {
+	rtb.appendLabel(newRichTextLabelMedia(fileKey, imgKey))
+	return rtb
+}
+
+// Emotion 表情标签
+//
+// https://open.feishu.cn/document/server-docs/im-v1/message-reaction/emojis-introduce
+func (rtb *RichTextBuilder) Emotion(emojiType string) *RichTextBuilder {
+	rtb.appendLabel(newRichTextLabelEmotion(emojiType))
+	return rtb
+}
+
+// CodeBlock 代码块标签，独占一个段落
+//
+// language: 代码块的语言类型，例如 GO、PYTHON、JSON，不区分大小写
+func (rtb *RichTextBuilder) CodeBlock(language, code string) *RichTextBuilder {
+	rtb.appendBlock(newRichTextLabelCodeBlock(language, code))
+	return rtb
+}
+
+// HorizontalRule 分割线标签，独占一个段落
+func (rtb *RichTextBuilder) HorizontalRule() *RichTextBuilder {
+	rtb.appendBlock(newRichTextLabelHorizontalRule())
+	return rtb
+}
+
+// Markdown md 标签，独占一个段落
+//
+// 支持的语法与限制参考飞书文档，md 标签不能与其他标签在同一个段落中
+//
+// https://open.feishu.cn/document/server-docs/im-v1/message-content-description/create_json#45e0953e
+func (rtb *RichTextBuilder) Markdown(md string) *RichTextBuilder {
+	rtb.appendBlock(newRichTextLabelMarkdown(md))
+	return rtb
+}
+
+// NewParagraph 开始新的段落（换行），之后添加的标签都在新的段落中
+//
+// 空段落在发送时会被忽略
+func (rtb *RichTextBuilder) NewParagraph() *RichTextBuilder {
+	rtb.body.Content = append(rtb.body.Content, make(richTextParagraph, 0, 4))
+	return rtb
+}
+
+// Paragraph 新增一个段落，由 fn 填充段落内容
+func (rtb *RichTextBuilder) Paragraph(fn func(pb *ParagraphBuilder)) *RichTextBuilder {
+	pb := NewRichTextParagraph()
+	fn(pb)
+	return rtb.AddParagraphs(pb)
+}
+
+// AddParagraphs 依次新增多个段落，之后通过 Text 等添加的标签仍在最后一个段落中
+func (rtb *RichTextBuilder) AddParagraphs(paragraphs ...*ParagraphBuilder) *RichTextBuilder {
+	for _, pb := range paragraphs {
+		if pb == nil {
+			continue
+		}
+		rtb.body.Content = append(rtb.body.Content, slices.Clone(pb.labels))
+	}
+	return rtb
+}
+
+// Paragraphs 当前的所有段落（包括空段落），返回的是副本，修改不会影响 rtb
+func (rtb *RichTextBuilder) Paragraphs() []*ParagraphBuilder {
+	ret := make([]*ParagraphBuilder, 0, len(rtb.body.Content))
+	for _, paragraph := range rtb.body.Content {
+		ret = append(ret, &ParagraphBuilder{labels: slices.Clone(paragraph)})
 	}
+	return ret
+}
+
+func (rtb *RichTextBuilder) appendLabel(lbl richTextLabel) {
 	paragraph := rtb.lastParagraph()
 	paragraph = append(paragraph, lbl)
 	rtb.updateLastParagraph(paragraph)
-	return rtb
+}
+
+// appendBlock 将 lbl 放入单独的段落，之后添加的标签在新的段落中
+func (rtb *RichTextBuilder) appendBlock(lbl richTextLabel) {
+	if len(rtb.lastParagraph()) != 0 {
+		rtb.NewParagraph()
+	}
+	rtb.appendLabel(lbl)
+	rtb.NewParagraph()
 }
 
 func (rtb *RichTextBuilder) lastParagraph() richTextParagraph {
@@ -192,3 +308,294 @@ func (rtb *RichTextBuilder) lastParagraph() richTextParagraph {
 func (rtb *RichTextBuilder) updateLastParagraph(paragraph richTextParagraph) {
 	rtb.body.Content[len(rtb.body.Content)-1] = paragraph
 }
+
+func (body richTextBody) trimEmptyParagraphs() richTextBody {
+	content := make([]richTextParagraph, 0, len(body.Content))
+	for _, paragraph := range body.Content {
+		if len(paragraph) == 0 {
+			continue
+		}
+		content = append(content, paragraph)
+	}
+	body.Content = content
+	return body
+}
+
+// --------------------------------------------------------------------------------
+
+// ParagraphBuilder 富文本中的一个段落
+type ParagraphBuilder struct {
+	labels richTextParagraph
+}
+
+func NewRichTextParagraph() *ParagraphBuilder {
+	return &ParagraphBuilder{labels: make(richTextParagraph, 0, 4)}
+}
+
+// Text 文本标签，参考 RichTextBuilder.Text
+func (pb *ParagraphBuilder) Text(text string, unEscape bool) *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelText(text, unEscape))
+	return pb
+}
+
+// Hyperlink 超链接标签，参考 RichTextBuilder.Hyperlink
+func (pb *ParagraphBuilder) Hyperlink(text, href string) *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelHyperlink(text, href))
+	return pb
+}
+
+// At @ 标签，参考 RichTextBuilder.At
+func (pb *ParagraphBuilder) At(id, name string) *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelAt(id, name))
+	return pb
+}
+
+func (pb *ParagraphBuilder) AtEveryone() *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelAt("all", ""))
+	return pb
+}
+
+// Image 图片标签，参考 RichTextBuilder.Image
+func (pb *ParagraphBuilder) Image(imgKey string) *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelImage(imgKey))
+	return pb
+}
+
+// TextWithStyle 带样式的文本标签
+func (pb *ParagraphBuilder) TextWithStyle(text string, unEscape bool, styles ...RichTextStyle) *ParagraphBuilder {
+	lbl := newRichTextLabelText(text, unEscape)
+	lbl.Style = styles
+	pb.labels = append(pb.labels, lbl)
+	return pb
+}
+
+// HyperlinkWithStyle 带样式的超链接标签
+func (pb *ParagraphBuilder) HyperlinkWithStyle(text, href string, styles ...RichTextStyle) *ParagraphBuilder {
+	lbl := newRichTextLabelHyperlink(text, href)
+	lbl.Style = styles
+	pb.labels = append(pb.labels, lbl)
+	return pb
+}
+
+// AtWithStyle 带样式的 @ 标签
+func (pb *ParagraphBuilder) AtWithStyle(id, name string, styles ...RichTextStyle) *ParagraphBuilder {
+	lbl := newRichTextLabelAt(id, name)
+	lbl.Style = styles
+	pb.labels = append(pb.labels, lbl)
+	return pb
+}
+
+// Media 视频标签，参考 RichTextBuilder.Media
+func (pb *ParagraphBuilder) Media(fileKey, imgKey string) *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelMedia(fileKey, imgKey))
+	return pb
+}
+
+// Emotion 表情标签，参考 RichTextBuilder.Emotion
+func (pb *ParagraphBuilder) Emotion(emojiType string) *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelEmotion(emojiType))
+	return pb
+}
+
+// CodeBlock 代码块标签，参考 RichTextBuilder.CodeBlock
+func (pb *ParagraphBuilder) CodeBlock(language, code string) *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelCodeBlock(language, code))
+	return pb
+}
+
+// HorizontalRule 分割线标签
+func (pb *ParagraphBuilder) HorizontalRule() *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelHorizontalRule())
+	return pb
+}
+
+// Markdown md 标签，所在段落不能再有其他标签，参考 RichTextBuilder.Markdown
+func (pb *ParagraphBuilder) Markdown(md string) *ParagraphBuilder {
+	pb.labels = append(pb.labels, newRichTextLabelMarkdown(md))
+	return pb
+}
+
+// Len 段落中标签的数量
+func (pb *ParagraphBuilder) Len() int {
+	return len(pb.labels)
+}
+
+// Tags 段落中各标签的类型，例如 text、a、at、img
+func (pb *ParagraphBuilder) Tags() []string {
+	ret := make([]string, 0, len(pb.labels))
+	for i := range pb.labels {
+		ret = append(ret, pb.labels[i].Tag)
+	}
+	return ret
+}
+
+// PlainText 段落的纯文本内容：文本、超链接的文字以及 @ 的名字
+func (pb *ParagraphBuilder) PlainText() string {
+	var sb strings.Builder
+	for _, lbl := range pb.labels {
+		switch lbl.Tag {
+		case "text", "a", "code_block", "md":
+			sb.WriteString(lbl.Text)
+		case "at":
+			sb.WriteString("@")
+			if lbl.UserID == "all" && lbl.UserName == "" {
+				sb.WriteString("all")
+			} else {
+				sb.WriteString(lbl.UserName)
+			}
+		}
+	}
+	return sb.String()
+}
+
+// --------------------------------------------------------------------------------
+
+func newRichTextLabelText(text string, unEscape bool) richTextLabel {
+	return richTextLabel{
+		Tag:      "text",
+		Text:     text,
+		UnEscape: &unEscape,
+	}
+}
+
+func newRichTextLabelHyperlink(text, href string) richTextLabel {
+	return richTextLabel{
+		Tag:  "a",
+		Text: text,
+		Href: href,
+	}
+}
+
+func newRichTextLabelAt(id, name string) richTextLabel {
+	return richTextLabel{
+		Tag:      "at",
+		UserID:   id,
+		UserName: name,
+	}
+}
+
+func newRichTextLabelImage(imgKey string) richTextLabel {
+	return richTextLabel{
+		Tag:      "img",
+		ImageKey: imgKey,
+	}
+}
+
+func newRichTextLabelMedia(fileKey, imgKey string) richTextLabel {
+	return richTextLabel{
+		Tag:      "media",
+		FileKey:  fileKey,
+		ImageKey: imgKey,
+	}
+}
+
+func newRichTextLabelEmotion(emojiType string) richTextLabel {
+	return richTextLabel{
+		Tag:       "emotion",
+		EmojiType: emojiType,
+	}
+}
+
+func newRichTextLabelCodeBlock(language, code string) richTextLabel {
+	return richTextLabel{
+		Tag:      "code_block",
+		Language: language,
+		Text:     code,
+	}
+}
+
+func newRichTextLabelHorizontalRule() richTextLabel {
+	return richTextLabel{Tag: "hr"}
+}
+
+func newRichTextLabelMarkdown(md string) richTextLabel {
+	return richTextLabel{
+		Tag:  "md",
+		Text: md,
+	}
+}
+
+// --------------------------------------------------------------------------------
+
+// ErrInvalidRichTextLabel 富文本标签包含不支持的字段或缺少必填字段
+var ErrInvalidRichTextLabel = errors.New("invalid rich text label")
+
+// _richTextLabelFields 各标签允许的字段，以及必填的字段
+var _richTextLabelFields = map[string]struct{ allowed, required []string }{
+	"text":       {allowed: []string{"text", "un_escape", "style"}},
+	"a":          {allowed: []string{"text", "href", "style"}, required: []string{"href"}},
+	"at":         {allowed: []string{"user_id", "user_name", "style"}, required: []string{"user_id"}},
+	"img":        {allowed: []string{"image_key"}, required: []string{"image_key"}},
+	"media":      {allowed: []string{"file_key", "image_key"}, required: []string{"file_key"}},
+	"emotion":    {allowed: []string{"emoji_type"}, required: []string{"emoji_type"}},
+	"code_block": {allowed: []string{"language", "text"}},
+	"hr":         {},
+	"md":         {allowed: []string{"text"}, required: []string{"text"}},
+}
+
+func (body richTextBody) validate() error {
+	for i, paragraph := range body.Content {
+		for j, lbl := range paragraph {
+			if err := lbl.validate(); err != nil {
+				return fmt.Errorf("paragraph(%d) label(%d): %w", i, j, err)
+			}
+			if lbl.Tag == "md" && len(paragraph) > 1 {
+				return fmt.Errorf("paragraph(%d) label(%d): %w: md must be the only label in its paragraph", i, j, ErrInvalidRichTextLabel)
+			}
+		}
+	}
+	return nil
+}
+
+func (lbl richTextLabel) validate() error {
+	fields, ok := _richTextLabelFields[lbl.Tag]
+	if !ok {
+		return fmt.Errorf("%w: unknown tag %q", ErrInvalidRichTextLabel, lbl.Tag)
+	}
+
+	present := lbl.fields()
+	for _, f := range present {
+		if !slices.Contains(fields.allowed, f) {
+			return fmt.Errorf("%w: %s does not allow %s", ErrInvalidRichTextLabel, lbl.Tag, f)
+		}
+	}
+	for _, f := range fields.required {
+		if !slices.Contains(present, f) {
+			return fmt.Errorf("%w: %s requires %s", ErrInvalidRichTextLabel, lbl.Tag, f)
+		}
+	}
+
+	for _, style := range lbl.Style {
+		switch style {
+		case RichTextStyleBold, RichTextStyleItalic, RichTextStyleUnderline, RichTextStyleLineThrough:
+		default:
+			return fmt.Errorf("%w: unknown style %q", ErrInvalidRichTextLabel, style)
+		}
+	}
+	return nil
+}
+
+// fields 已设置的字段（json 名称）
+func (lbl richTextLabel) fields() []string {
+	ret := make([]string, 0, 4)
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"text", lbl.Text != ""},
+		{"un_escape", lbl.UnEscape != nil},
+		{"style", len(lbl.Style) != 0},
+		{"href", lbl.Href != ""},
+		{"user_id", lbl.UserID != ""},
+		{"user_name", lbl.UserName != ""},
+		{"image_key", lbl.ImageKey != ""},
+		{"file_key", lbl.FileKey != ""},
+		{"emoji_type", lbl.EmojiType != ""},
+		{"language", lbl.Language != ""},
+	} {
+		if f.set {
+			ret = append(ret, f.name)
+		}
+	}
+	return ret
+}
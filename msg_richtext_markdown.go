@@ -0,0 +1,139 @@
+package feishu_bot_api
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/electricbubble/feishu-bot-api/v2/internal/mdparse"
+)
+
+// NewRichTextFromMarkdown 将 Markdown 文档转换为富文本，参考 RichTextBuilder.AppendMarkdown
+func NewRichTextFromMarkdown(language Language, title, markdown string) *RichTextBuilder {
+	return NewRichText(language, title).AppendMarkdown(markdown)
+}
+
+// AppendMarkdown 将 Markdown（CommonMark 以及 GFM 删除线）转换为富文本段落，追加在新的段落中
+//
+//   - 标题: 加粗的段落
+//   - 加粗、斜体、删除线: 对应的文本样式
+//   - 链接: 超链接标签；图片: 以替代文本（为空时为图片地址）作为文字的超链接
+//   - 行内代码: 保留反引号的文本
+//   - 代码块: 代码块标签
+//   - 分割线: 分割线标签
+//   - 列表: 以 "• " 或 "1. " 开头的段落，嵌套列表逐级缩进
+//   - 引用: 以 "> " 开头的段落
+//   - 软换行为空格，硬换行开始新的段落；HTML 等不支持的语法按原文保留
+func (rtb *RichTextBuilder) AppendMarkdown(markdown string) *RichTextBuilder {
+	rtb.NewParagraph()
+	rtb.appendMarkdownBlocks(mdparse.Parse(markdown), "", "")
+	return rtb
+}
+
+// appendMarkdownBlocks first 为第一个段落的前缀，rest 为之后段落的前缀
+func (rtb *RichTextBuilder) appendMarkdownBlocks(blocks []mdparse.Block, first, rest string) {
+	for i, block := range blocks {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+
+		switch block.Kind {
+		case mdparse.BlockParagraph:
+			rtb.appendMarkdownInlines(block.Inlines, 0, prefix, rest)
+		case mdparse.BlockHeading:
+			rtb.appendMarkdownInlines(block.Inlines, mdparse.StyleBold, prefix, rest)
+		case mdparse.BlockCode:
+			rtb.appendMarkdownMarker(prefix, rest)
+			rtb.CodeBlock(strings.ToUpper(block.Language), block.Code)
+		case mdparse.BlockThematicBreak:
+			rtb.appendMarkdownMarker(prefix, rest)
+			rtb.HorizontalRule()
+		case mdparse.BlockQuote:
+			rtb.appendMarkdownBlocks(block.Children, prefix+"> ", rest+"> ")
+		case mdparse.BlockList:
+			for j, item := range block.Items {
+				marker := "• "
+				if block.Ordered {
+					marker = strconv.Itoa(block.Start+j) + ". "
+				}
+				itemPrefix := rest
+				if j == 0 {
+					itemPrefix = prefix
+				}
+				rtb.appendMarkdownBlocks(item, itemPrefix+marker, rest+strings.Repeat(" ", utf8.RuneCountInString(marker)))
+			}
+		}
+	}
+}
+
+// appendMarkdownMarker 代码块、分割线作为列表项的第一个块时，列表标记单独占一个段落
+func (rtb *RichTextBuilder) appendMarkdownMarker(prefix, rest string) {
+	if prefix == rest {
+		return
+	}
+	rtb.appendMarkdownInlines(nil, 0, strings.TrimRight(prefix, " "), rest)
+}
+
+func (rtb *RichTextBuilder) appendMarkdownInlines(inlines []mdparse.Inline, style mdparse.Style, first, rest string) {
+	if len(rtb.lastParagraph()) != 0 {
+		rtb.NewParagraph()
+	}
+	if first != "" {
+		rtb.Text(first, false)
+	}
+
+	for _, inl := range inlines {
+		styles := markdownRichTextStyles(inl.Style | style)
+
+		var text string
+		switch inl.Kind {
+		case mdparse.InlineSoftBreak:
+			rtb.Text(" ", false)
+			continue
+		case mdparse.InlineHardBreak:
+			rtb.NewParagraph()
+			if rest != "" {
+				rtb.Text(rest, false)
+			}
+			continue
+		case mdparse.InlineCode:
+			text = "`" + inl.Text + "`"
+		case mdparse.InlineImage:
+			text = inl.Text
+			if text == "" {
+				text = inl.Src
+			}
+			if inl.Link == "" {
+				inl.Link = inl.Src
+			}
+		default:
+			text = inl.Text
+		}
+
+		switch {
+		case text == "":
+		case inl.Link != "":
+			rtb.HyperlinkWithStyle(text, inl.Link, styles...)
+		case len(styles) != 0:
+			rtb.TextWithStyle(text, false, styles...)
+		default:
+			rtb.Text(text, false)
+		}
+	}
+	rtb.NewParagraph()
+}
+
+func markdownRichTextStyles(style mdparse.Style) []RichTextStyle {
+	var styles []RichTextStyle
+	if style&mdparse.StyleBold != 0 {
+		styles = append(styles, RichTextStyleBold)
+	}
+	if style&mdparse.StyleItalic != 0 {
+		styles = append(styles, RichTextStyleItalic)
+	}
+	if style&mdparse.StyleStrikethrough != 0 {
+		styles = append(styles, RichTextStyleLineThrough)
+	}
+	return styles
+}
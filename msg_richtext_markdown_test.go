@@ -0,0 +1,86 @@
+package feishu_bot_api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewRichTextFromMarkdown(t *testing.T) {
+	const markdown = "# Release *v1.2*\n" +
+		"\n" +
+		"Deploy **api** and ~~web~~ with `make deploy`, see [docs](https://example.com/docs).\n" +
+		"Second line  \n" +
+		"after hard break\n" +
+		"\n" +
+		"- one\n" +
+		"- two\n" +
+		"  1. nested\n" +
+		"- ```\n" +
+		"  code\n" +
+		"  ```\n" +
+		"\n" +
+		"> quoted\n" +
+		"\n" +
+		"---\n" +
+		"\n" +
+		"```go\n" +
+		"panic(err)\n" +
+		"```\n" +
+		"![logo](https://example.com/logo.png) <b>html</b>"
+
+	rtb := NewRichTextFromMarkdown(LanguageChinese, "发布", markdown)
+
+	want := []struct {
+		text string
+		tags []string
+	}{
+		{text: "Release v1.2", tags: []string{"text", "text"}},
+		{text: "Deploy api and web with `make deploy`, see docs. Second line", tags: []string{"text", "text", "text", "text", "text", "text", "text", "a", "text", "text", "text"}},
+		{text: "after hard break", tags: []string{"text"}},
+		{text: "• one", tags: []string{"text", "text"}},
+		{text: "• two", tags: []string{"text", "text"}},
+		{text: "  1. nested", tags: []string{"text", "text"}},
+		{text: "•", tags: []string{"text"}},
+		{text: "code", tags: []string{"code_block"}},
+		{text: "> quoted", tags: []string{"text", "text"}},
+		{text: "", tags: []string{"hr"}},
+		{text: "panic(err)", tags: []string{"code_block"}},
+		{text: "logo <b>html</b>", tags: []string{"a", "text"}},
+	}
+
+	var got []*ParagraphBuilder
+	for _, pb := range rtb.Paragraphs() {
+		if pb.Len() != 0 {
+			got = append(got, pb)
+		}
+	}
+	if len(got) != len(want) {
+		for _, pb := range got {
+			t.Logf("%q %v", pb.PlainText(), pb.Tags())
+		}
+		t.Fatalf("Actual paragraphs: %d, want: %d", len(got), len(want))
+	}
+	for i := range want {
+		if text := got[i].PlainText(); text != want[i].text {
+			t.Errorf("paragraph(%d): Actual text: %q, want: %q", i, text, want[i].text)
+		}
+		if tags := got[i].Tags(); !reflect.DeepEqual(tags, want[i].tags) {
+			t.Errorf("paragraph(%d): Actual tags: %v, want: %v", i, tags, want[i].tags)
+		}
+	}
+
+	raw, err := richTextMessage{rtb}.marshal()
+	requireNoError(t, err)
+	for _, s := range []string{
+		`{"tag":"text","text":"Release ","un_escape":false,"style":["bold"]},{"tag":"text","text":"v1.2","un_escape":false,"style":["bold","italic"]}`,
+		`{"tag":"text","text":"web","un_escape":false,"style":["lineThrough"]}`,
+		`{"tag":"a","text":"docs","href":"https://example.com/docs"}`,
+		`{"tag":"code_block","text":"panic(err)","language":"GO"}`,
+		`{"tag":"a","text":"logo","href":"https://example.com/logo.png"}`,
+	} {
+		if !strings.Contains(string(raw), s) {
+			t.Errorf("Actual:\n%s\nwant contains:\n%s", raw, s)
+		}
+	}
+}
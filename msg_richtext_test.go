@@ -0,0 +1,198 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestRichTextBuilder_paragraphs(t *testing.T) {
+	tests := []struct {
+		name string
+		rtb  *RichTextBuilder
+		want string
+	}{
+		{
+			name: "single",
+			rtb:  NewRichText(LanguageChinese, "标题").Text("a", false).Text("b", false),
+			want: `{"zh_cn":{"title":"标题","content":[[{"tag":"text","text":"a","un_escape":false},{"tag":"text","text":"b","un_escape":false}]]}}`,
+		},
+		{
+			name: "new_paragraph",
+			rtb: NewRichText(LanguageChinese, "标题").
+				Text("a", false).
+				NewParagraph().Hyperlink("b", "https://open.feishu.cn").
+				NewParagraph().AtEveryone(),
+			want: `{"zh_cn":{"title":"标题","content":[[{"tag":"text","text":"a","un_escape":false}],[{"tag":"a","text":"b","href":"https://open.feishu.cn"}],[{"tag":"at","user_id":"all"}]]}}`,
+		},
+		{
+			name: "paragraph_func",
+			rtb: NewRichText(LanguageChinese, "部署").
+				Paragraph(func(pb *ParagraphBuilder) { pb.Text("api: ", false).Text("ok", false) }).
+				Paragraph(func(pb *ParagraphBuilder) { pb.Text("web: ", false).At("ou_xxx", "Tom") }),
+			want: `{"zh_cn":{"title":"部署","content":[[{"tag":"text","text":"api: ","un_escape":false},{"tag":"text","text":"ok","un_escape":false}],[{"tag":"text","text":"web: ","un_escape":false},{"tag":"at","user_id":"ou_xxx","user_name":"Tom"}]]}}`,
+		},
+		{
+			name: "trim_empty",
+			rtb: NewRichText(LanguageChinese, "标题").
+				NewParagraph().NewParagraph().
+				Text("a", false).
+				AddParagraphs(NewRichTextParagraph(), nil).
+				NewParagraph(),
+			want: `{"zh_cn":{"title":"标题","content":[[{"tag":"text","text":"a","un_escape":false}]]}}`,
+		},
+		{
+			name: "all_empty",
+			rtb:  NewRichText(LanguageChinese, "标题").NewParagraph(),
+			want: `{"zh_cn":{"title":"标题"}}`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := richTextMessage{tt.rtb}.marshal()
+			requireNoError(t, err)
+			if string(got) != tt.want {
+				t.Errorf("Actual:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRichTextBuilder_Paragraphs(t *testing.T) {
+	line := NewRichTextParagraph().Text("web: ", false).Hyperlink("v1.2.3", "https://example.com").Image("img_xxx")
+
+	rtb := NewRichText(LanguageChinese, "部署").
+		Text("api: ok", false).
+		AddParagraphs(line, line).
+		Text(" ", false).AtEveryone()
+
+	ps := rtb.Paragraphs()
+	if len(ps) != 3 {
+		t.Fatalf("Actual paragraphs: %d, want: 3", len(ps))
+	}
+
+	wantTags := [][]string{
+		{"text"},
+		{"text", "a", "img"},
+		{"text", "a", "img", "text", "at"},
+	}
+	wantTexts := []string{"api: ok", "web: v1.2.3", "web: v1.2.3 @all"}
+	for i := range ps {
+		if got := ps[i].Tags(); !reflect.DeepEqual(got, wantTags[i]) {
+			t.Errorf("paragraph(%d): Actual tags: %v, want: %v", i, got, wantTags[i])
+		}
+		if got := ps[i].PlainText(); got != wantTexts[i] {
+			t.Errorf("paragraph(%d): Actual text: %q, want: %q", i, got, wantTexts[i])
+		}
+	}
+
+	// 修改副本与原段落均不影响 rtb
+	ps[0].Text("x", false)
+	line.Text("y", false)
+	if got := rtb.Paragraphs()[0].Len(); got != 1 {
+		t.Errorf("Actual len: %d, want: 1", got)
+	}
+	if got := rtb.Paragraphs()[1].Len(); got != 3 {
+		t.Errorf("Actual len: %d, want: 3", got)
+	}
+}
+
+func TestRichTextBuilder_tags(t *testing.T) {
+	rtb := NewRichText(LanguageChinese, "告警").
+		TextWithStyle("api", false, RichTextStyleBold, RichTextStyleUnderline).
+		HyperlinkWithStyle("详情", "https://example.com", RichTextStyleItalic).
+		AtWithStyle("ou_xxx", "Tom", RichTextStyleLineThrough).
+		Emotion("SMILE").
+		CodeBlock("GO", "panic: oops").
+		Text("after", false).
+		HorizontalRule().
+		Markdown("**md**").
+		Media("file_xxx", "img_xxx")
+
+	got, err := richTextMessage{rtb}.marshal()
+	requireNoError(t, err)
+
+	want := `{"zh_cn":{"title":"告警","content":[` +
+		`[{"tag":"text","text":"api","un_escape":false,"style":["bold","underline"]},{"tag":"a","text":"详情","style":["italic"],"href":"https://example.com"},{"tag":"at","style":["lineThrough"],"user_id":"ou_xxx","user_name":"Tom"},{"tag":"emotion","emoji_type":"SMILE"}],` +
+		`[{"tag":"code_block","text":"panic: oops","language":"GO"}],` +
+		`[{"tag":"text","text":"after","un_escape":false}],` +
+		`[{"tag":"hr"}],` +
+		`[{"tag":"md","text":"**md**"}],` +
+		`[{"tag":"media","image_key":"img_xxx","file_key":"file_xxx"}]]}}`
+	if string(got) != want {
+		t.Errorf("Actual:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestRichTextBuilder_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rtb     *RichTextBuilder
+		wantErr bool
+	}{
+		{
+			name: "paragraph_tags",
+			rtb: NewRichText(LanguageChinese, "").AddParagraphs(
+				NewRichTextParagraph().Emotion("OK").TextWithStyle("x", false, RichTextStyleBold),
+				NewRichTextParagraph().CodeBlock("", "x").HorizontalRule(),
+				NewRichTextParagraph().Markdown("**md**"),
+			),
+		},
+		{
+			name:    "md_with_other_labels",
+			rtb:     NewRichText(LanguageChinese, "").AddParagraphs(NewRichTextParagraph().Text("x", false).Markdown("**md**")),
+			wantErr: true,
+		},
+		{
+			name:    "unknown_style",
+			rtb:     NewRichText(LanguageChinese, "").TextWithStyle("x", false, "blink"),
+			wantErr: true,
+		},
+		{
+			name:    "missing_href",
+			rtb:     NewRichText(LanguageChinese, "").Hyperlink("x", ""),
+			wantErr: true,
+		},
+		{
+			name:    "missing_emoji_type",
+			rtb:     NewRichText(LanguageChinese, "").Emotion(""),
+			wantErr: true,
+		},
+		{
+			name:    "missing_file_key",
+			rtb:     NewRichText(LanguageChinese, "").Media("", "img_xxx"),
+			wantErr: true,
+		},
+		{
+			name: "field_not_allowed",
+			rtb: func() *RichTextBuilder {
+				rtb := NewRichText(LanguageChinese, "").Image("img_xxx")
+				rtb.body.Content[0][0].Style = []RichTextStyle{RichTextStyleBold}
+				return rtb
+			}(),
+			wantErr: true,
+		},
+		{
+			name: "unknown_tag",
+			rtb: func() *RichTextBuilder {
+				rtb := NewRichText(LanguageChinese, "")
+				rtb.appendLabel(richTextLabel{Tag: "blink"})
+				return rtb
+			}(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := richTextMessage{tt.rtb}.marshal()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Actual err: %v, wantErr: %t", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidRichTextLabel) {
+				t.Errorf("Actual err: %v, want: %v", err, ErrInvalidRichTextLabel)
+			}
+		})
+	}
+}
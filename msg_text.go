@@ -1,6 +1,11 @@
 package feishu_bot_api
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
 
 var _ Message = (*textMessage)(nil)
 
@@ -31,3 +36,102 @@ func TextAtPerson(id, name string) string {
 func TextAtEveryone() string {
 	return `<at user_id="all">everyone</at>`
 }
+
+// --------------------------------------------------------------------------------
+
+var _ Message = (*TextBuilder)(nil)
+
+// TextBuilder 文本消息构建器，Text 传入的内容会被转义，不会产生意外的 @ 效果
+//
+// TextBuilder 实现了 Message，可以通过 SendMessage 发送
+type TextBuilder struct {
+	sb  strings.Builder
+	err error
+}
+
+func NewText() *TextBuilder {
+	return &TextBuilder{}
+}
+
+var _textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+// EscapeText 转义文本消息中的 &、<、>
+func EscapeText(s string) string {
+	return _textEscaper.Replace(s)
+}
+
+// Text 文本内容，& < > 会被转义
+func (tb *TextBuilder) Text(s string) *TextBuilder {
+	tb.sb.WriteString(EscapeText(s))
+	return tb
+}
+
+// At @指定人，name 会被转义；id 不能为空，且不能包含 "、<、>、& 或空白字符，否则 Apply 时返回 ErrInvalidTextAt
+//
+// 参考 TextAtPerson
+func (tb *TextBuilder) At(id, name string) *TextBuilder {
+	if !_reTextAtID.MatchString(id) {
+		if tb.err == nil {
+			tb.err = fmt.Errorf("%w: user_id %q", ErrInvalidTextAt, id)
+		}
+		return tb
+	}
+	tb.sb.WriteString(TextAtPerson(id, EscapeText(name)))
+	return tb
+}
+
+// AtEveryone @所有人
+//
+// 参考 TextAtEveryone
+func (tb *TextBuilder) AtEveryone() *TextBuilder {
+	tb.sb.WriteString(TextAtEveryone())
+	return tb
+}
+
+// Line 换行
+func (tb *TextBuilder) Line() *TextBuilder {
+	tb.sb.WriteString("\n")
+	return tb
+}
+
+// String 构建好的文本内容，也可以通过 SendText 发送
+func (tb *TextBuilder) String() string {
+	return tb.sb.String()
+}
+
+func (tb *TextBuilder) Apply(body *MessageBody) error {
+	if tb.err != nil {
+		return fmt.Errorf("text message: %w", tb.err)
+	}
+	content := tb.String()
+	if err := ValidateText(content); err != nil {
+		return fmt.Errorf("text message: %w", err)
+	}
+	return textMessage(content).Apply(body)
+}
+
+// ErrInvalidTextAt 文本消息中的 <at> 标签格式不正确
+var ErrInvalidTextAt = errors.New("invalid <at> markup")
+
+var (
+	_reTextAtStart = regexp.MustCompile(`</?at[\s>/]`)
+	_reTextAt      = regexp.MustCompile(`^<at user_id="[^"<>&\s]+">[^<>]*</at>`)
+	_reTextAtID    = regexp.MustCompile(`^[^"<>&\s]+$`)
+)
+
+// ValidateText 检查文本消息中的 <at> 标签是否完整，例如 <at user_id="ou_xxx">Tom</at>
+func ValidateText(content string) error {
+	for offset := 0; ; {
+		loc := _reTextAtStart.FindStringIndex(content[offset:])
+		if loc == nil {
+			return nil
+		}
+		start := offset + loc[0]
+
+		m := _reTextAt.FindString(content[start:])
+		if m == "" {
+			return fmt.Errorf("%w at offset %d", ErrInvalidTextAt, start)
+		}
+		offset = start + len(m)
+	}
+}
@@ -0,0 +1,100 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTextBuilder(t *testing.T) {
+	tests := []struct {
+		name string
+		tb   *TextBuilder
+		want string
+	}{
+		{
+			name: "empty",
+			tb:   NewText(),
+			want: "",
+		},
+		{
+			name: "escape",
+			tb:   NewText().Text(`branch <at user_id="all">x</at> & more`),
+			want: `branch &lt;at user_id="all"&gt;x&lt;/at&gt; &amp; more`,
+		},
+		{
+			name: "at",
+			tb:   NewText().Text("build failed ").At("ou_xxx", "<Tom>").Line().AtEveryone(),
+			want: "build failed <at user_id=\"ou_xxx\">&lt;Tom&gt;</at>\n<at user_id=\"all\">everyone</at>",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.tb.String(); got != tt.want {
+				t.Fatalf("Actual: %q, want: %q", got, tt.want)
+			}
+
+			var body MessageBody
+			requireNoError(t, tt.tb.Apply(&body))
+			if body.MsgType != "text" || body.Content == nil || body.Content.Text != tt.want {
+				t.Errorf("Actual: %+v, want text %q", body, tt.want)
+			}
+		})
+	}
+}
+
+func TestTextBuilder_invalidAt(t *testing.T) {
+	tests := []struct {
+		name string
+		tb   *TextBuilder
+	}{
+		{name: "quote", tb: NewText().At(`ou_"xxx`, "Tom")},
+		{name: "empty", tb: NewText().At("", "Tom")},
+		{name: "space", tb: NewText().At("ou_x y", "Tom")},
+		{name: "crafted", tb: NewText().Text("deploy by ").At(`ou_x">x</at><at user_id="all`, "bob")},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var body MessageBody
+			err := tt.tb.Apply(&body)
+			if !errors.Is(err, ErrInvalidTextAt) {
+				t.Errorf("Actual err: %v, want: %v", err, ErrInvalidTextAt)
+			}
+			if strings.Contains(tt.tb.String(), "<at") {
+				t.Errorf("Actual text %q, want no <at> markup", tt.tb.String())
+			}
+		})
+	}
+}
+
+func TestValidateText(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{name: "plain", content: "a < b && c > d"},
+		{name: "at", content: "hi " + TextAtPerson("ou_xxx", "Tom") + " " + TextAtEveryone()},
+		{name: "not_at", content: "<atom> <a href>"},
+		{name: "escaped", content: NewText().Text(`<at user_id="all">`).String()},
+		{name: "unclosed", content: `<at user_id="all">everyone`, wantErr: true},
+		{name: "unquoted", content: `<at user_id=all>everyone</at>`, wantErr: true},
+		{name: "empty_id", content: `<at user_id="">x</at>`, wantErr: true},
+		{name: "nested", content: `<at user_id="a"><at user_id="b">x</at></at>`, wantErr: true},
+		{name: "stray_close", content: `hi</at>`, wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateText(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Actual err: %v, wantErr: %t", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidTextAt) {
+				t.Errorf("Actual err: %v, want: %v", err, ErrInvalidTextAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+var _ Bot = (*multiBot)(nil)
+
+// MultiBotTarget 广播的目标 webhook
+type MultiBotTarget struct {
+	Webhook string
+
+	// Options 每个 webhook 独立的配置（包括 SecretKey、限流等），为空时使用默认配置
+	Options *BotOptions
+}
+
+type MultiBotOptions struct {
+	// Concurrency 同时发送的 webhook 数量上限，默认 4
+	Concurrency int
+}
+
+func NewMultiBotOptions() *MultiBotOptions { return &MultiBotOptions{} }
+
+func (opts *MultiBotOptions) init() {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = 4
+	}
+}
+
+func (opts *MultiBotOptions) SetConcurrency(n int) *MultiBotOptions {
+	opts.Concurrency = n
+	return opts
+}
+
+// WebhookError 某个 webhook 发送失败
+type WebhookError struct {
+	// Webhook 脱敏后的 webhook access token
+	Webhook string
+
+	Err error
+}
+
+func (e *WebhookError) Error() string {
+	return fmt.Sprintf("webhook(%s): %v", e.Webhook, e.Err)
+}
+
+func (e *WebhookError) Unwrap() error {
+	return e.Err
+}
+
+// MultiError 广播时部分（或全部）webhook 发送失败
+//
+// 可以通过 errors.Is / errors.As 匹配其中任意一个 webhook 的错误
+type MultiError struct {
+	// Total 广播的 webhook 总数
+	Total int
+
+	// Errors 发送失败的 webhook，顺序与 NewMultiBot 传入的 targets 一致
+	Errors []*WebhookError
+}
+
+func (e *MultiError) Error() string {
+	ss := make([]string, len(e.Errors))
+	for i := range e.Errors {
+		ss[i] = e.Errors[i].Error()
+	}
+	return fmt.Sprintf("%d of %d webhooks failed: %s", len(e.Errors), e.Total, strings.Join(ss, "; "))
+}
+
+func (e *MultiError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i := range e.Errors {
+		errs[i] = e.Errors[i]
+	}
+	return errs
+}
+
+type multiBot struct {
+	sendMethods
+
+	bots []*bot
+	opts *MultiBotOptions
+}
+
+// NewMultiBot 将同一条消息广播到多个 webhook
+//
+// 消息只会 Apply 一次，之后并发发送到所有 webhook；存在发送失败的 webhook 时返回 *MultiError
+func NewMultiBot(targets []MultiBotTarget, opts *MultiBotOptions) Bot {
+	if opts == nil {
+		opts = &MultiBotOptions{}
+	}
+	opts.init()
+
+	mb := &multiBot{
+		bots: make([]*bot, 0, len(targets)),
+		opts: opts,
+	}
+	mb.sendMethods = sendMethods{sendMessage: mb.sendMessage}
+
+	for _, target := range targets {
+		mb.bots = append(mb.bots, NewBot(target.Webhook, target.Options).(*bot))
+	}
+
+	return mb
+}
+
+func (mb *multiBot) sendMessage(ctx context.Context, msg Message) error {
+	var body MessageBody
+	if err := msg.Apply(&body); err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
+
+	var (
+		errs = make([]error, len(mb.bots))
+		sem  = make(chan struct{}, mb.opts.Concurrency)
+		wg   sync.WaitGroup
+	)
+LOOP:
+	for i := range mb.bots {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for j := i; j < len(mb.bots); j++ {
+				errs[j] = ctx.Err()
+			}
+			break LOOP
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			errs[i] = mb.bots[i].SendMessageContext(withSendResult(ctx, nil), body)
+		}(i)
+	}
+	wg.Wait()
+
+	var multiErr *MultiError
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if multiErr == nil {
+			multiErr = &MultiError{Total: len(mb.bots)}
+		}
+		multiErr.Errors = append(multiErr.Errors, &WebhookError{
+			Webhook: maskToken(mb.bots[i].webhookAccessToken),
+			Err:     err,
+		})
+	}
+	if multiErr != nil {
+		return multiErr
+	}
+	return nil
+}
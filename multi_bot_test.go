@@ -0,0 +1,72 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestMultiBot(t *testing.T) {
+	var (
+		running, maxRunning atomic.Int32
+		received            atomic.Int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			m := maxRunning.Load()
+			if n <= m || maxRunning.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		received.Add(1)
+
+		if strings.HasPrefix(path.Base(r.URL.Path), "bad") {
+			_, _ = w.Write([]byte(`{"code":19024,"msg":"Key Words Not Found"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer srv.Close()
+
+	targets := []MultiBotTarget{
+		{Webhook: "good-token-0001", Options: NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())},
+		{Webhook: "bad-token-0002", Options: NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetSecretKey("secret")},
+		{Webhook: "good-token-0003", Options: NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())},
+		{Webhook: "good-token-0004", Options: NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())},
+		{Webhook: "bad-token-0005", Options: NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())},
+	}
+	b := NewMultiBot(targets, NewMultiBotOptions().SetConcurrency(2))
+
+	err := b.SendText("release")
+	if n := received.Load(); n != int32(len(targets)) {
+		t.Errorf("Actual received: %d, want: %d", n, len(targets))
+	}
+	if n := maxRunning.Load(); n > 2 {
+		t.Errorf("Actual max concurrency: %d, want: <= 2", n)
+	}
+
+	var multiErr *MultiError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("Actual error: %v, want: %T", err, multiErr)
+	}
+	if multiErr.Total != len(targets) || len(multiErr.Errors) != 2 {
+		t.Fatalf("Actual: %v", multiErr)
+	}
+	if w := multiErr.Errors[0].Webhook; w != "bad-****0002" {
+		t.Errorf("Actual webhook: %s, want: bad-****0002", w)
+	}
+	if w := multiErr.Errors[1].Webhook; w != "bad-****0005" {
+		t.Errorf("Actual webhook: %s, want: bad-****0005", w)
+	}
+	if !errors.Is(err, ErrKeywordNotMatched) {
+		t.Errorf("Actual error: %v, want: %v", err, ErrKeywordNotMatched)
+	}
+}
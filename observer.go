@@ -0,0 +1,90 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"time"
+)
+
+// Observer 观察发送流程中的各类事件，用于指标、链路追踪等
+//
+// 方法会在发送流程中同步调用，实现需要是并发安全的，且不应阻塞。
+// 只关心部分事件时可以嵌入 NopObserver
+type Observer interface {
+	// LimiterWaitStart 开始等待限流器
+	LimiterWaitStart(ctx context.Context, e LimiterWaitEvent)
+	// LimiterWaitEnd 结束等待限流器
+	LimiterWaitEnd(ctx context.Context, e LimiterWaitEvent)
+	// RequestSent 发送 HTTP 请求之前
+	RequestSent(ctx context.Context, e RequestEvent)
+	// ResponseReceived HTTP 请求结束之后（包括网络错误）
+	ResponseReceived(ctx context.Context, e ResponseEvent)
+	// Retry 发送失败，即将重试
+	Retry(ctx context.Context, e RetryEvent)
+	// Drop 发送失败且不再重试，消息被丢弃
+	Drop(ctx context.Context, e DropEvent)
+}
+
+// LimiterWaitEvent 限流等待事件
+type LimiterWaitEvent struct {
+	// Webhook 脱敏后的 webhook access token
+	Webhook string
+	// Duration 等待时长，仅 LimiterWaitEnd 有效
+	Duration time.Duration
+	// Err 等待失败的原因（例如 ctx 被取消），仅 LimiterWaitEnd 有效
+	Err error
+}
+
+// RequestEvent 请求事件
+type RequestEvent struct {
+	Webhook      string
+	MsgType      string
+	PayloadBytes int
+	// Attempt 第几次尝试，从 1 开始
+	Attempt int
+}
+
+// ResponseEvent 响应事件
+type ResponseEvent struct {
+	Webhook string
+	MsgType string
+	Attempt int
+	Latency time.Duration
+	// Code 飞书返回的业务码，未收到响应时为 0
+	Code int
+	Msg  string
+	// StatusCode HTTP 状态码，未收到响应时为 0
+	StatusCode int
+	// LogID 响应头 X-Tt-Logid
+	LogID string
+	Err   error
+}
+
+// RetryEvent 重试事件
+type RetryEvent struct {
+	Webhook string
+	MsgType string
+	// Attempt 失败的是第几次尝试
+	Attempt int
+	Backoff time.Duration
+	Err     error
+}
+
+// DropEvent 丢弃事件
+type DropEvent struct {
+	Webhook  string
+	MsgType  string
+	Attempts int
+	Err      error
+}
+
+var _ Observer = NopObserver{}
+
+// NopObserver 忽略所有事件
+type NopObserver struct{}
+
+func (NopObserver) LimiterWaitStart(context.Context, LimiterWaitEvent) {}
+func (NopObserver) LimiterWaitEnd(context.Context, LimiterWaitEvent)   {}
+func (NopObserver) RequestSent(context.Context, RequestEvent)          {}
+func (NopObserver) ResponseReceived(context.Context, ResponseEvent)    {}
+func (NopObserver) Retry(context.Context, RetryEvent)                  {}
+func (NopObserver) Drop(context.Context, DropEvent)                    {}
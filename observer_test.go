@@ -0,0 +1,90 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// recordingObserver 按顺序记录事件
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *recordingObserver) record(format string, args ...any) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.events = append(o.events, fmt.Sprintf(format, args...))
+}
+
+func (o *recordingObserver) LimiterWaitStart(_ context.Context, e LimiterWaitEvent) {
+	o.record("limiter_wait_start %s", e.Webhook)
+}
+
+func (o *recordingObserver) LimiterWaitEnd(_ context.Context, e LimiterWaitEvent) {
+	o.record("limiter_wait_end %s %v", e.Webhook, e.Err)
+}
+
+func (o *recordingObserver) RequestSent(_ context.Context, e RequestEvent) {
+	o.record("request_sent %s %s %d %v", e.Webhook, e.MsgType, e.Attempt, e.PayloadBytes > 0)
+}
+
+func (o *recordingObserver) ResponseReceived(_ context.Context, e ResponseEvent) {
+	o.record("response_received %s %s %d %d %d %v", e.Webhook, e.MsgType, e.Attempt, e.Code, e.StatusCode, e.Err != nil)
+}
+
+func (o *recordingObserver) Retry(_ context.Context, e RetryEvent) {
+	o.record("retry %s %d", e.Webhook, e.Attempt)
+}
+
+func (o *recordingObserver) Drop(_ context.Context, e DropEvent) {
+	o.record("drop %s %d", e.Webhook, e.Attempts)
+}
+
+func TestObserver(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte(`{"code":9499,"msg":"too many request"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"code":19024,"msg":"Key Words Not Found"}`))
+	}))
+	defer srv.Close()
+
+	const token = "0123456789abcdef"
+	obs := new(recordingObserver)
+	opts := NewBotOptions().
+		SetBaseURL(srv.URL).
+		SetLimiterRegistry(NewLimiterRegistry()).
+		SetRetry(NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond)).
+		SetObserver(obs)
+
+	if err := NewBot(token, opts).SendText("hi"); err == nil {
+		t.Fatal("Expected an error")
+	}
+
+	want := []string{
+		"limiter_wait_start 0123****cdef",
+		"limiter_wait_end 0123****cdef <nil>",
+		"request_sent 0123****cdef text 1 true",
+		"response_received 0123****cdef text 1 9499 429 true",
+		"retry 0123****cdef 1",
+		"limiter_wait_start 0123****cdef",
+		"limiter_wait_end 0123****cdef <nil>",
+		"request_sent 0123****cdef text 2 true",
+		"response_received 0123****cdef text 2 19024 200 true",
+		"drop 0123****cdef 2",
+	}
+	if !reflect.DeepEqual(obs.events, want) {
+		t.Errorf("Actual events:\n%q\nwant:\n%q", obs.events, want)
+	}
+}
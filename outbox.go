@@ -0,0 +1,589 @@
+package feishu_bot_api
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrMessageEvicted 消息因超出 OutboxOptions.MaxSpoolSize 被淘汰，通过 OutboxOptions.OnEvict 回调
+var ErrMessageEvicted = errors.New("outbox: message evicted")
+
+type OutboxOptions struct {
+	// SegmentSize 单个分段文件的大小上限（字节），超出后写入新的分段文件，默认 4 MiB
+	SegmentSize int64
+
+	// MaxSpoolSize 目录中所有分段文件（包括当前写入的分段文件）的大小上限（字节），超出后从最早的分段文件开始淘汰未发送的消息。0 表示不限制
+	//
+	// 小于 SegmentSize 时，分段文件按 MaxSpoolSize 的 1/4 切换，以便按较小的粒度淘汰
+	MaxSpoolSize int64
+
+	// OnEvict 消息被淘汰时的回调，在释放发件箱的锁之后调用
+	OnEvict func(body MessageBody, err error)
+
+	// OnReplay NewOutbox 在后台补发目录中未发送的消息，补发结束后的回调；Close 时仍未结束则 err 为 context.Canceled
+	OnReplay func(err error)
+}
+
+func NewOutboxOptions() *OutboxOptions { return &OutboxOptions{} }
+
+func (opts *OutboxOptions) init() {
+	if opts.SegmentSize <= 0 {
+		opts.SegmentSize = 4 << 20
+	}
+}
+
+func (opts *OutboxOptions) SetSegmentSize(n int64) *OutboxOptions {
+	opts.SegmentSize = n
+	return opts
+}
+
+func (opts *OutboxOptions) SetMaxSpoolSize(n int64) *OutboxOptions {
+	opts.MaxSpoolSize = n
+	return opts
+}
+
+func (opts *OutboxOptions) SetOnEvict(f func(body MessageBody, err error)) *OutboxOptions {
+	opts.OnEvict = f
+	return opts
+}
+
+func (opts *OutboxOptions) SetOnReplay(f func(err error)) *OutboxOptions {
+	opts.OnReplay = f
+	return opts
+}
+
+// Outbox 持久化发件箱
+//
+// 消息在发送前（Message.Apply 之后）先追加写入目录中的分段文件，直到接口返回 code 0 才标记删除，
+// 进程重启后 NewOutbox 会在后台补发未成功发送的消息，也可以通过 Replay 手动补发
+//
+// 分段文件中的每条记录格式为：4 字节长度 + 4 字节 CRC-32C 校验和 + JSON 内容，
+// 单条记录的 JSON 内容不能超过 MaxSpoolSize（未设置时为 SegmentSize）
+type Outbox struct {
+	bot  Bot
+	dir  string
+	opts *OutboxOptions
+
+	mu       sync.Mutex
+	nextID   uint64
+	lastSeq  uint64
+	segments []*outboxSegment
+	active   *os.File
+	pending  map[uint64]*outboxEntry
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+type outboxSegment struct {
+	seq     uint64
+	path    string
+	size    int64
+	pending int
+}
+
+type outboxEntry struct {
+	id        uint64
+	createdAt time.Time
+	body      MessageBody
+	segment   *outboxSegment
+	inflight  bool
+	evicted   bool
+}
+
+const (
+	outboxOpPut = "put"
+	outboxOpAck = "ack"
+
+	outboxSegmentPrefix = "outbox-"
+	outboxSegmentSuffix = ".seg"
+)
+
+type outboxRecord struct {
+	Op        string       `json:"op"`
+	ID        uint64       `json:"id"`
+	CreatedAt int64        `json:"created_at,omitempty"`
+	Body      *MessageBody `json:"body,omitempty"`
+}
+
+var _outboxCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// NewOutbox 打开（或创建）dir 作为发件箱目录，加载其中未发送的消息并在后台补发，补发结果通过 OutboxOptions.OnReplay 回调
+func NewOutbox(b Bot, dir string, opts *OutboxOptions) (*Outbox, error) {
+	if opts == nil {
+		opts = &OutboxOptions{}
+	}
+	opts.init()
+
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("outbox: mkdir: %w", err)
+	}
+
+	o := &Outbox{
+		bot:     b,
+		dir:     dir,
+		opts:    opts,
+		nextID:  1,
+		pending: make(map[uint64]*outboxEntry),
+	}
+	if err := o.load(); err != nil {
+		return nil, fmt.Errorf("outbox: load: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	o.cancel = cancel
+	o.done = make(chan struct{})
+	go o.replayOnOpen(ctx)
+
+	return o, nil
+}
+
+// Send 持久化消息后再发送
+//
+// 发送失败时消息保留在发件箱中，可以通过 Replay 重新发送；消息在发送前已被淘汰时返回 ErrMessageEvicted
+func (o *Outbox) Send(ctx context.Context, msg Message) error {
+	var body MessageBody
+	if err := msg.Apply(&body); err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
+
+	o.mu.Lock()
+	e, evicted, err := o.put(body)
+	o.mu.Unlock()
+	o.notifyEvicted(evicted)
+	if err != nil {
+		return fmt.Errorf("outbox: %w", err)
+	}
+
+	return o.deliver(ctx, e)
+}
+
+// Replay 按写入顺序重新发送所有未发送的消息
+//
+// ctx 被取消时立即返回；其他错误不会中断后续消息的发送，最终合并返回
+func (o *Outbox) Replay(ctx context.Context) error {
+	o.mu.Lock()
+	entries := make([]*outboxEntry, 0, len(o.pending))
+	for _, e := range o.pending {
+		if e.inflight {
+			continue
+		}
+		e.inflight = true
+		entries = append(entries, e)
+	}
+	o.mu.Unlock()
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+
+	var errs []error
+	for i, e := range entries {
+		if err := ctx.Err(); err != nil {
+			o.release(entries[i:])
+			return err
+		}
+		if err := o.deliver(ctx, e); err != nil && !errors.Is(err, ErrMessageEvicted) {
+			errs = append(errs, fmt.Errorf("outbox(%d): %w", e.id, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (o *Outbox) replayOnOpen(ctx context.Context) {
+	defer close(o.done)
+
+	err := o.Replay(ctx)
+	if f := o.opts.OnReplay; f != nil {
+		f(err)
+	}
+}
+
+// Pending 未发送的消息数量
+func (o *Outbox) Pending() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.pending)
+}
+
+// PendingAge 最早一条未发送消息的等待时长，没有未发送的消息时返回 0
+func (o *Outbox) PendingAge() time.Duration {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	var oldest time.Time
+	for _, e := range o.pending {
+		if oldest.IsZero() || e.createdAt.Before(oldest) {
+			oldest = e.createdAt
+		}
+	}
+	if oldest.IsZero() {
+		return 0
+	}
+	return time.Since(oldest)
+}
+
+// Close 停止后台补发并关闭当前写入的分段文件，未发送的消息保留在目录中
+func (o *Outbox) Close() error {
+	o.cancel()
+	<-o.done
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.active == nil {
+		return nil
+	}
+	err := o.active.Close()
+	o.active = nil
+	return err
+}
+
+func (o *Outbox) deliver(ctx context.Context, e *outboxEntry) error {
+	o.mu.Lock()
+	evicted := e.evicted
+	o.mu.Unlock()
+	if evicted {
+		// 已通过 OnEvict 回调，不再发送
+		return ErrMessageEvicted
+	}
+
+	err := o.bot.SendMessageContext(ctx, e.body)
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	e.inflight = false
+	if err != nil {
+		return err
+	}
+
+	if e.evicted {
+		// 发送期间已被淘汰
+		return nil
+	}
+	if err := o.ack(e); err != nil {
+		return fmt.Errorf("outbox: %w", err)
+	}
+	return nil
+}
+
+func (o *Outbox) release(entries []*outboxEntry) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	for _, e := range entries {
+		e.inflight = false
+	}
+}
+
+// put 写入一条消息，返回被淘汰的消息，调用方需要在释放锁之后通过 notifyEvicted 回调
+func (o *Outbox) put(body MessageBody) (*outboxEntry, []*outboxEntry, error) {
+	e := &outboxEntry{
+		id:        o.nextID,
+		createdAt: time.Now(),
+		body:      body,
+		inflight:  true,
+	}
+
+	seg, err := o.append(outboxRecord{Op: outboxOpPut, ID: e.id, CreatedAt: e.createdAt.UnixNano(), Body: &body})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	o.nextID++
+	e.segment = seg
+	seg.pending++
+	o.pending[e.id] = e
+
+	return e, o.evict(), nil
+}
+
+func (o *Outbox) notifyEvicted(entries []*outboxEntry) {
+	f := o.opts.OnEvict
+	if f == nil {
+		return
+	}
+	for _, e := range entries {
+		f(e.body, ErrMessageEvicted)
+	}
+}
+
+func (o *Outbox) ack(e *outboxEntry) error {
+	if _, err := o.append(outboxRecord{Op: outboxOpAck, ID: e.id}); err != nil {
+		return err
+	}
+
+	delete(o.pending, e.id)
+	e.segment.pending--
+
+	return o.compact()
+}
+
+// append 写入一条记录并落盘，返回记录所在的分段文件
+func (o *Outbox) append(rec outboxRecord) (*outboxSegment, error) {
+	payload, err := json.Marshal(rec)
+	if err != nil {
+		return nil, fmt.Errorf("marshal: %w", err)
+	}
+	if limit := o.maxRecordSize(); int64(len(payload)) > limit {
+		return nil, fmt.Errorf("record size %d exceeds %d", len(payload), limit)
+	}
+
+	seg, err := o.activeSegment()
+	if err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, 8+len(payload))
+	binary.BigEndian.PutUint32(buf[0:4], uint32(len(payload)))
+	binary.BigEndian.PutUint32(buf[4:8], crc32.Checksum(payload, _outboxCRCTable))
+	copy(buf[8:], payload)
+
+	n, err := o.active.Write(buf)
+	seg.size += int64(n)
+	if err != nil {
+		return nil, fmt.Errorf("write: %w", err)
+	}
+	if err := o.active.Sync(); err != nil {
+		return nil, fmt.Errorf("sync: %w", err)
+	}
+	return seg, nil
+}
+
+// activeSegment 返回当前写入的分段文件，超出 segmentSize 时切换到新的分段文件
+func (o *Outbox) activeSegment() (*outboxSegment, error) {
+	if n := len(o.segments); n > 0 && o.active != nil && o.segments[n-1].size < o.segmentSize() {
+		return o.segments[n-1], nil
+	}
+
+	if err := o.closeActive(); err != nil {
+		return nil, err
+	}
+
+	o.lastSeq++
+	seg := &outboxSegment{
+		seq:  o.lastSeq,
+		path: filepath.Join(o.dir, fmt.Sprintf("%s%016x%s", outboxSegmentPrefix, o.lastSeq, outboxSegmentSuffix)),
+	}
+	f, err := os.OpenFile(seg.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("open segment: %w", err)
+	}
+	o.active = f
+	o.segments = append(o.segments, seg)
+
+	if err := o.compact(); err != nil {
+		return nil, err
+	}
+	return seg, nil
+}
+
+// closeActive 关闭当前写入的分段文件，之后的记录写入新的分段文件
+func (o *Outbox) closeActive() error {
+	if o.active == nil {
+		return nil
+	}
+	err := o.active.Close()
+	o.active = nil
+	if err != nil {
+		return fmt.Errorf("close segment: %w", err)
+	}
+	return nil
+}
+
+// segmentSize 分段文件的大小上限
+func (o *Outbox) segmentSize() int64 {
+	size := o.opts.SegmentSize
+	if limit := o.opts.MaxSpoolSize; limit > 0 && limit < size {
+		size = max(limit/4, 1)
+	}
+	return size
+}
+
+// maxRecordSize 单条记录 JSON 内容的大小上限
+func (o *Outbox) maxRecordSize() int64 {
+	if limit := o.opts.MaxSpoolSize; limit > 0 {
+		return limit
+	}
+	return o.opts.SegmentSize
+}
+
+// compact 从最早的分段文件开始，删除所有消息均已发送的分段文件
+//
+// 分段文件只能按顺序删除：后面分段文件中的 ack 记录可能对应前面分段文件中的消息
+func (o *Outbox) compact() error {
+	for len(o.segments) > 1 && o.segments[0].pending == 0 {
+		if err := os.Remove(o.segments[0].path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove segment: %w", err)
+		}
+		o.segments = o.segments[1:]
+	}
+	return nil
+}
+
+// evict 超出 MaxSpoolSize 时，淘汰最早的分段文件中未发送的消息，返回被淘汰的消息
+//
+// 只剩当前写入的分段文件且仍超出上限时（例如该分段文件中已有的记录加上新消息超过 MaxSpoolSize），先切换到新的分段文件再淘汰
+func (o *Outbox) evict() (evicted []*outboxEntry) {
+	if o.opts.MaxSpoolSize <= 0 {
+		return nil
+	}
+
+	for len(o.segments) > 0 && o.spoolSize() > o.opts.MaxSpoolSize {
+		if len(o.segments) == 1 {
+			if err := o.closeActive(); err != nil {
+				return evicted
+			}
+		}
+		seg := o.segments[0]
+
+		entries := make([]*outboxEntry, 0, seg.pending)
+		for _, e := range o.pending {
+			if e.segment == seg {
+				entries = append(entries, e)
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].id < entries[j].id })
+
+		for _, e := range entries {
+			delete(o.pending, e.id)
+			e.evicted = true
+		}
+		evicted = append(evicted, entries...)
+		seg.pending = 0
+
+		if err := os.Remove(seg.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return evicted
+		}
+		o.segments = o.segments[1:]
+	}
+	return evicted
+}
+
+func (o *Outbox) spoolSize() int64 {
+	var size int64
+	for _, seg := range o.segments {
+		size += seg.size
+	}
+	return size
+}
+
+func (o *Outbox) load() error {
+	des, err := os.ReadDir(o.dir)
+	if err != nil {
+		return err
+	}
+
+	for _, de := range des {
+		name := de.Name()
+		if de.IsDir() || !strings.HasPrefix(name, outboxSegmentPrefix) || !strings.HasSuffix(name, outboxSegmentSuffix) {
+			continue
+		}
+
+		var seq uint64
+		if _, err := fmt.Sscanf(strings.TrimSuffix(strings.TrimPrefix(name, outboxSegmentPrefix), outboxSegmentSuffix), "%x", &seq); err != nil {
+			continue
+		}
+		o.segments = append(o.segments, &outboxSegment{seq: seq, path: filepath.Join(o.dir, name)})
+	}
+	sort.Slice(o.segments, func(i, j int) bool { return o.segments[i].seq < o.segments[j].seq })
+	if n := len(o.segments); n > 0 {
+		o.lastSeq = o.segments[n-1].seq
+	}
+
+	for i, seg := range o.segments {
+		if err := o.loadSegment(seg, i == len(o.segments)-1); err != nil {
+			return fmt.Errorf("%s: %w", filepath.Base(seg.path), err)
+		}
+	}
+
+	if len(o.segments) > 0 {
+		seg := o.segments[len(o.segments)-1]
+		f, err := os.OpenFile(seg.path, os.O_WRONLY|os.O_APPEND, 0o600)
+		if err != nil {
+			return fmt.Errorf("open segment: %w", err)
+		}
+		o.active = f
+	}
+
+	return o.compact()
+}
+
+// loadSegment 读取分段文件中的记录
+//
+// 遇到不完整、超出 maxRecordSize 或校验失败的记录时停止读取；如果是最后一个分段文件，则截断该记录（进程在写入时退出）
+func (o *Outbox) loadSegment(seg *outboxSegment, last bool) error {
+	f, err := os.Open(seg.path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+
+	var (
+		r      = bufio.NewReader(f)
+		header = make([]byte, 8)
+		offset int64
+	)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			break
+		}
+		size := binary.BigEndian.Uint32(header[0:4])
+		if int64(size) > o.maxRecordSize() {
+			break
+		}
+		payload := make([]byte, size)
+		if _, err := io.ReadFull(r, payload); err != nil {
+			break
+		}
+		if crc32.Checksum(payload, _outboxCRCTable) != binary.BigEndian.Uint32(header[4:8]) {
+			break
+		}
+
+		var rec outboxRecord
+		if err := json.Unmarshal(payload, &rec); err != nil {
+			break
+		}
+		offset += 8 + int64(size)
+
+		switch rec.Op {
+		case outboxOpPut:
+			if rec.Body == nil {
+				continue
+			}
+			o.pending[rec.ID] = &outboxEntry{
+				id:        rec.ID,
+				createdAt: time.Unix(0, rec.CreatedAt),
+				body:      *rec.Body,
+				segment:   seg,
+			}
+			seg.pending++
+		case outboxOpAck:
+			if e, ok := o.pending[rec.ID]; ok {
+				delete(o.pending, rec.ID)
+				e.segment.pending--
+			}
+		}
+		if rec.ID >= o.nextID {
+			o.nextID = rec.ID + 1
+		}
+	}
+	seg.size = offset
+
+	if fi, err := f.Stat(); err == nil && fi.Size() > offset && last {
+		if err := os.Truncate(seg.path, offset); err != nil {
+			return fmt.Errorf("truncate: %w", err)
+		}
+	}
+	return nil
+}
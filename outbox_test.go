@@ -0,0 +1,291 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func newSwitchableServer(t *testing.T, healthy *atomic.Bool, sent *atomic.Int32) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		sent.Add(1)
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+// replayDone 返回等待 NewOutbox 后台补发结束的 OutboxOptions
+func replayDone(opts *OutboxOptions) (*OutboxOptions, <-chan error) {
+	done := make(chan error, 1)
+	return opts.SetOnReplay(func(err error) { done <- err }), done
+}
+
+func TestOutbox(t *testing.T) {
+	t.Run("send_and_replay", func(t *testing.T) {
+		var (
+			healthy atomic.Bool
+			sent    atomic.Int32
+			srv     = newSwitchableServer(t, &healthy, &sent)
+			dir     = t.TempDir()
+			b       = NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()))
+		)
+
+		o, err := NewOutbox(b, dir, nil)
+		requireNoError(t, err)
+
+		var apiErr *APIError
+		if err := o.Send(context.Background(), textMessage("lost")); !errors.As(err, &apiErr) {
+			t.Fatalf("Actual error: %v, want: %T", err, apiErr)
+		}
+		healthy.Store(true)
+		requireNoError(t, o.Send(context.Background(), textMessage("ok")))
+		if n := o.Pending(); n != 1 {
+			t.Fatalf("Actual pending: %d, want: 1", n)
+		}
+		if o.PendingAge() <= 0 {
+			t.Errorf("Actual pending age: %s, want: > 0", o.PendingAge())
+		}
+		requireNoError(t, o.Close())
+
+		// 模拟进程重启，NewOutbox 在后台补发
+		opts, done := replayDone(NewOutboxOptions())
+		o, err = NewOutbox(b, dir, opts)
+		requireNoError(t, err)
+		requireNoError(t, <-done)
+		if n := o.Pending(); n != 0 {
+			t.Errorf("Actual pending after reopen: %d, want: 0", n)
+		}
+		if n := sent.Load(); n != 2 {
+			t.Errorf("Actual sent: %d, want: 2", n)
+		}
+		requireNoError(t, o.Close())
+
+		o, err = NewOutbox(b, dir, nil)
+		requireNoError(t, err)
+		if n := o.Pending(); n != 0 {
+			t.Errorf("Actual pending after second reopen: %d, want: 0", n)
+		}
+		requireNoError(t, o.Close())
+	})
+
+	t.Run("torn_write", func(t *testing.T) {
+		var (
+			healthy atomic.Bool
+			sent    atomic.Int32
+			srv     = newSwitchableServer(t, &healthy, &sent)
+			dir     = t.TempDir()
+			b       = NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()))
+		)
+
+		o, err := NewOutbox(b, dir, nil)
+		requireNoError(t, err)
+		_ = o.Send(context.Background(), textMessage("lost"))
+		requireNoError(t, o.Close())
+
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.seg"))
+		if len(matches) != 1 {
+			t.Fatalf("Actual segments: %v", matches)
+		}
+		f, err := os.OpenFile(matches[0], os.O_WRONLY|os.O_APPEND, 0o600)
+		requireNoError(t, err)
+		_, _ = f.Write([]byte{0, 0, 1, 0, 1, 2, 3})
+		requireNoError(t, f.Close())
+
+		opts, done := replayDone(NewOutboxOptions())
+		o, err = NewOutbox(b, dir, opts)
+		requireNoError(t, err)
+		var apiErr *APIError
+		if err := <-done; !errors.As(err, &apiErr) {
+			t.Fatalf("Actual replay error: %v, want: %T", err, apiErr)
+		}
+		if n := o.Pending(); n != 1 {
+			t.Fatalf("Actual pending: %d, want: 1", n)
+		}
+		healthy.Store(true)
+		requireNoError(t, o.Replay(context.Background()))
+		requireNoError(t, o.Close())
+
+		o, err = NewOutbox(b, dir, nil)
+		requireNoError(t, err)
+		if n := o.Pending(); n != 0 {
+			t.Errorf("Actual pending: %d, want: 0", n)
+		}
+		requireNoError(t, o.Close())
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		var (
+			healthy atomic.Bool
+			sent    atomic.Int32
+			srv     = newSwitchableServer(t, &healthy, &sent)
+			b       = NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()))
+			evicted []string
+		)
+
+		opts := NewOutboxOptions().SetSegmentSize(1).SetMaxSpoolSize(200).SetOnEvict(func(body MessageBody, err error) {
+			if !errors.Is(err, ErrMessageEvicted) {
+				t.Errorf("Actual error: %v, want: %v", err, ErrMessageEvicted)
+			}
+			evicted = append(evicted, body.Content.Text)
+		})
+		o, err := NewOutbox(b, t.TempDir(), opts)
+		requireNoError(t, err)
+		defer func() { _ = o.Close() }()
+
+		for _, text := range []string{"1", "2", "3", "4"} {
+			_ = o.Send(context.Background(), textMessage(text))
+		}
+		if len(evicted) == 0 || evicted[0] != "1" {
+			t.Errorf("Actual evicted: %v", evicted)
+		}
+		if n := o.Pending(); n+len(evicted) != 4 {
+			t.Errorf("Actual pending: %d, evicted: %d", n, len(evicted))
+		}
+	})
+
+	t.Run("evict_below_segment_size", func(t *testing.T) {
+		var (
+			healthy atomic.Bool
+			sent    atomic.Int32
+			srv     = newSwitchableServer(t, &healthy, &sent)
+			b       = NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetLimiterPerSecond(-1))
+			dir     = t.TempDir()
+			evicted int
+		)
+
+		// 默认的 SegmentSize 为 4 MiB，远大于 MaxSpoolSize
+		const maxSpoolSize = 1024
+		opts := NewOutboxOptions().SetMaxSpoolSize(maxSpoolSize).SetOnEvict(func(MessageBody, error) { evicted++ })
+		o, err := NewOutbox(b, dir, opts)
+		requireNoError(t, err)
+		defer func() { _ = o.Close() }()
+
+		const total = 200
+		for i := 0; i < total; i++ {
+			_ = o.Send(context.Background(), textMessage("message"))
+
+			var size int64
+			des, err := os.ReadDir(dir)
+			requireNoError(t, err)
+			for _, de := range des {
+				fi, err := de.Info()
+				requireNoError(t, err)
+				size += fi.Size()
+			}
+			if size > maxSpoolSize {
+				t.Fatalf("send(%d): Actual spool size: %d, want: <= %d", i, size, maxSpoolSize)
+			}
+		}
+
+		if evicted == 0 {
+			t.Error("Expected OnEvict to be called")
+		}
+		pending := o.Pending()
+		if pending == 0 || pending+evicted != total {
+			t.Errorf("Actual pending: %d, evicted: %d, want a total of %d", pending, evicted, total)
+		}
+
+		requireNoError(t, o.Close())
+		opts, done := replayDone(opts)
+		o, err = NewOutbox(b, dir, opts)
+		requireNoError(t, err)
+		<-done
+		if n := o.Pending(); n != pending {
+			t.Errorf("Actual pending after reopen: %d, want: %d", n, pending)
+		}
+	})
+
+	t.Run("evict_before_deliver", func(t *testing.T) {
+		var (
+			healthy atomic.Bool
+			sent    atomic.Int32
+			srv     = newSwitchableServer(t, &healthy, &sent)
+			b       = NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetLimiterPerSecond(-1))
+			o       *Outbox
+			evicted []string
+		)
+		healthy.Store(true)
+
+		// OnEvict 中调用 Pending、Send 不会死锁
+		opts := NewOutboxOptions().SetMaxSpoolSize(1024).SetOnEvict(func(body MessageBody, err error) {
+			evicted = append(evicted, body.Content.Text)
+			if n := o.Pending(); n != 0 {
+				t.Errorf("Actual pending in OnEvict: %d, want: 0", n)
+			}
+			requireNoError(t, o.Send(context.Background(), textMessage("from OnEvict")))
+		})
+		o, err := NewOutbox(b, t.TempDir(), opts)
+		requireNoError(t, err)
+		defer func() { _ = o.Close() }()
+
+		requireNoError(t, o.Send(context.Background(), textMessage("small")))
+
+		// 当前分段文件中已有的记录加上这条消息超出 MaxSpoolSize，这条消息在发送前被淘汰
+		large := strings.Repeat("x", 850)
+		if err := o.Send(context.Background(), textMessage(large)); !errors.Is(err, ErrMessageEvicted) {
+			t.Fatalf("Actual error: %v, want: %v", err, ErrMessageEvicted)
+		}
+		if len(evicted) != 1 || evicted[0] != large {
+			t.Errorf("Actual evicted: %d messages, want the large one", len(evicted))
+		}
+		if n := sent.Load(); n != 2 {
+			t.Errorf("Actual sent: %d, want: 2", n)
+		}
+	})
+
+	t.Run("corrupt_record_size", func(t *testing.T) {
+		var (
+			healthy atomic.Bool
+			sent    atomic.Int32
+			srv     = newSwitchableServer(t, &healthy, &sent)
+			dir     = t.TempDir()
+			b       = NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()))
+		)
+
+		opts, done := replayDone(NewOutboxOptions())
+		o, err := NewOutbox(b, dir, opts)
+		requireNoError(t, err)
+		<-done
+		_ = o.Send(context.Background(), textMessage("lost"))
+		requireNoError(t, o.Close())
+
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.seg"))
+		if len(matches) != 1 {
+			t.Fatalf("Actual segments: %v", matches)
+		}
+		before, err := os.Stat(matches[0])
+		requireNoError(t, err)
+		f, err := os.OpenFile(matches[0], os.O_WRONLY|os.O_APPEND, 0o600)
+		requireNoError(t, err)
+		// 记录长度远超 SegmentSize
+		_, _ = f.Write([]byte{0xff, 0xff, 0xff, 0xff, 0, 0, 0, 0})
+		requireNoError(t, f.Close())
+
+		opts, done = replayDone(NewOutboxOptions())
+		o, err = NewOutbox(b, dir, opts)
+		requireNoError(t, err)
+		<-done
+		if n := o.Pending(); n != 1 {
+			t.Errorf("Actual pending: %d, want: 1", n)
+		}
+		requireNoError(t, o.Close())
+
+		after, err := os.Stat(matches[0])
+		requireNoError(t, err)
+		if after.Size() != before.Size() {
+			t.Errorf("Actual segment size: %d, want truncated to: %d", after.Size(), before.Size())
+		}
+	})
+}
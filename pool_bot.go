@@ -0,0 +1,157 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var _ Bot = (*poolBot)(nil)
+
+// PoolStrategy 选择 webhook 的策略
+type PoolStrategy int
+
+const (
+	// PoolStrategyRoundRobin 轮询
+	PoolStrategyRoundRobin PoolStrategy = iota
+	// PoolStrategyMostTokens 优先选择限流器剩余令牌最多的 webhook
+	PoolStrategyMostTokens
+)
+
+type PoolBotOptions struct {
+	Strategy PoolStrategy
+
+	// Cooldown webhook 返回频率限制或机器人停用的错误后，在该时间内优先尝试其他 webhook，默认 1 分钟
+	Cooldown time.Duration
+}
+
+func NewPoolBotOptions() *PoolBotOptions { return &PoolBotOptions{} }
+
+func (opts *PoolBotOptions) init() {
+	if opts.Cooldown <= 0 {
+		opts.Cooldown = time.Minute
+	}
+}
+
+func (opts *PoolBotOptions) SetStrategy(strategy PoolStrategy) *PoolBotOptions {
+	opts.Strategy = strategy
+	return opts
+}
+
+func (opts *PoolBotOptions) SetCooldown(d time.Duration) *PoolBotOptions {
+	opts.Cooldown = d
+	return opts
+}
+
+type poolBot struct {
+	sendMethods
+
+	members []*poolMember
+	opts    *PoolBotOptions
+	next    atomic.Uint64
+}
+
+type poolMember struct {
+	*bot
+
+	mu            sync.Mutex
+	cooldownUntil time.Time
+}
+
+// NewPoolBot 将消息分摊到同一群组中的多个 webhook，以突破单个机器人的频率限制
+//
+// 每条消息只发送到一个 webhook；该 webhook 返回频率限制（ErrRateLimited）或机器人停用（ErrBotDisabled）时，
+// 自动切换到下一个 webhook。所有 webhook 均失败时返回 *MultiError
+func NewPoolBot(targets []MultiBotTarget, opts *PoolBotOptions) Bot {
+	if opts == nil {
+		opts = &PoolBotOptions{}
+	}
+	opts.init()
+
+	pb := &poolBot{
+		members: make([]*poolMember, 0, len(targets)),
+		opts:    opts,
+	}
+	pb.sendMethods = sendMethods{sendMessage: pb.sendMessage}
+
+	for _, target := range targets {
+		pb.members = append(pb.members, &poolMember{bot: NewBot(target.Webhook, target.Options).(*bot)})
+	}
+
+	return pb
+}
+
+func (pb *poolBot) sendMessage(ctx context.Context, msg Message) error {
+	if len(pb.members) == 0 {
+		return errors.New("pool: no webhook")
+	}
+
+	var body MessageBody
+	if err := msg.Apply(&body); err != nil {
+		return fmt.Errorf("apply: %w", err)
+	}
+
+	multiErr := &MultiError{Total: len(pb.members)}
+	for _, m := range pb.candidates() {
+		err := m.SendMessageContext(ctx, body)
+		if err == nil {
+			return nil
+		}
+
+		multiErr.Errors = append(multiErr.Errors, &WebhookError{
+			Webhook: maskToken(m.webhookAccessToken),
+			Err:     err,
+		})
+
+		if !errors.Is(err, ErrRateLimited) && !errors.Is(err, ErrBotDisabled) {
+			return err
+		}
+		m.cooldown(pb.opts.Cooldown)
+	}
+
+	return multiErr
+}
+
+// candidates 按策略排序后的 webhook，处于冷却期的排在最后
+func (pb *poolBot) candidates() []*poolMember {
+	n := len(pb.members)
+	ms := make([]*poolMember, 0, n)
+
+	switch pb.opts.Strategy {
+	case PoolStrategyMostTokens:
+		ms = append(ms, pb.members...)
+		tokens := make(map[*poolMember]float64, n)
+		for _, m := range ms {
+			tokens[m] = m.tokens()
+		}
+		sort.SliceStable(ms, func(i, j int) bool { return tokens[ms[i]] > tokens[ms[j]] })
+	default:
+		start := int((pb.next.Add(1) - 1) % uint64(n))
+		for i := 0; i < n; i++ {
+			ms = append(ms, pb.members[(start+i)%n])
+		}
+	}
+
+	cooling := make(map[*poolMember]bool, n)
+	for _, m := range ms {
+		cooling[m] = m.coolingDown()
+	}
+	sort.SliceStable(ms, func(i, j int) bool { return !cooling[ms[i]] && cooling[ms[j]] })
+	return ms
+}
+
+func (m *poolMember) cooldown(d time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cooldownUntil = m.clock.Now().Add(d)
+}
+
+func (m *poolMember) coolingDown() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.clock.Now().Before(m.cooldownUntil)
+}
@@ -0,0 +1,138 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/electricbubble/feishu-bot-api/v2/clock/clocktest"
+)
+
+func TestPoolBot(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = make(map[string]int)
+		limited  = make(map[string]bool)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := path.Base(r.URL.Path)
+
+		mu.Lock()
+		defer mu.Unlock()
+		if limited[token] {
+			_, _ = w.Write([]byte(`{"code":9499,"msg":"too many request"}`))
+			return
+		}
+		received[token]++
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success"}`))
+	}))
+	defer srv.Close()
+
+	reset := func(limitedTokens ...string) {
+		mu.Lock()
+		defer mu.Unlock()
+		received = make(map[string]int)
+		limited = make(map[string]bool)
+		for _, token := range limitedTokens {
+			limited[token] = true
+		}
+	}
+	targets := func(opts ...*BotOptions) []MultiBotTarget {
+		ts := []MultiBotTarget{
+			{Webhook: "token-a", Options: NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())},
+			{Webhook: "token-b", Options: NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())},
+			{Webhook: "token-c", Options: NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry())},
+		}
+		for i := range opts {
+			ts[i].Options = opts[i]
+		}
+		return ts
+	}
+
+	t.Run("round_robin", func(t *testing.T) {
+		reset()
+		b := NewPoolBot(targets(), nil)
+		for i := 0; i < 6; i++ {
+			requireNoError(t, b.SendText("hi"))
+		}
+		for _, token := range []string{"token-a", "token-b", "token-c"} {
+			if n := received[token]; n != 2 {
+				t.Errorf("Actual received(%s): %d, want: 2", token, n)
+			}
+		}
+	})
+
+	t.Run("failover", func(t *testing.T) {
+		reset("token-a")
+		b := NewPoolBot(targets(), nil)
+		for i := 0; i < 4; i++ {
+			requireNoError(t, b.SendText("hi"))
+		}
+		if n := received["token-a"]; n != 0 {
+			t.Errorf("Actual received(token-a): %d, want: 0", n)
+		}
+		if n := received["token-b"] + received["token-c"]; n != 4 {
+			t.Errorf("Actual received(token-b, token-c): %d, want: 4", n)
+		}
+	})
+
+	t.Run("all_limited", func(t *testing.T) {
+		reset("token-a", "token-b", "token-c")
+		err := NewPoolBot(targets(), nil).SendText("hi")
+
+		var multiErr *MultiError
+		if !errors.As(err, &multiErr) || len(multiErr.Errors) != 3 {
+			t.Fatalf("Actual error: %v, want: %T", err, multiErr)
+		}
+		if !errors.Is(err, ErrRateLimited) {
+			t.Errorf("Actual error: %v, want: %v", err, ErrRateLimited)
+		}
+	})
+
+	t.Run("cooldown", func(t *testing.T) {
+		reset("token-a")
+		fc := clocktest.NewFakeClock(time.Now())
+		opts := func() *BotOptions {
+			return NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetClock(fc).SetLimiterPerSecond(-1)
+		}
+		b := NewPoolBot(targets(opts(), opts(), opts()), NewPoolBotOptions().SetCooldown(time.Minute))
+
+		requireNoError(t, b.SendText("hi"))
+		reset()
+
+		// token-a 冷却中，轮到它时排在最后
+		for i := 0; i < 3; i++ {
+			requireNoError(t, b.SendText("hi"))
+		}
+		if n := received["token-a"]; n != 0 {
+			t.Errorf("Actual received(token-a) during cooldown: %d, want: 0", n)
+		}
+
+		fc.Advance(time.Minute)
+		for i := 0; i < 3; i++ {
+			requireNoError(t, b.SendText("hi"))
+		}
+		if n := received["token-a"]; n != 1 {
+			t.Errorf("Actual received(token-a) after cooldown: %d, want: 1", n)
+		}
+	})
+
+	t.Run("most_tokens", func(t *testing.T) {
+		reset()
+		registry := NewLimiterRegistry()
+		b := NewPoolBot(targets(
+			NewBotOptions().SetBaseURL(srv.URL).SetLimiterPerSecond(1).SetLimiterPerMinute(10).SetLimiterRegistry(registry),
+			NewBotOptions().SetBaseURL(srv.URL).SetLimiterPerSecond(5).SetLimiterPerMinute(10).SetLimiterRegistry(registry),
+			NewBotOptions().SetBaseURL(srv.URL).SetLimiterPerSecond(2).SetLimiterPerMinute(10).SetLimiterRegistry(registry),
+		), NewPoolBotOptions().SetStrategy(PoolStrategyMostTokens))
+
+		requireNoError(t, b.SendText("hi"))
+		if n := received["token-b"]; n != 1 {
+			t.Errorf("Actual received: %v", received)
+		}
+	})
+}
@@ -0,0 +1,221 @@
+package feishu_bot_api
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	_ Observer     = (*PrometheusObserver)(nil)
+	_ http.Handler = (*PrometheusObserver)(nil)
+)
+
+// DefaultPrometheusBuckets 直方图默认的桶（单位秒）
+var DefaultPrometheusBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60}
+
+// PrometheusObserver 以 Prometheus 文本格式暴露指标的 Observer，同时也是 http.Handler
+//
+// 指标按脱敏后的 webhook access token 区分：
+//
+//	feishu_bot_limiter_wait_seconds      限流等待时长（直方图）
+//	feishu_bot_requests_total            请求数
+//	feishu_bot_request_bytes_total       请求体字节数
+//	feishu_bot_responses_total           响应数，按飞书业务码、HTTP 状态码区分（未收到响应时均为 0）
+//	feishu_bot_response_latency_seconds  请求耗时（直方图）
+//	feishu_bot_retries_total             重试次数
+//	feishu_bot_drops_total               丢弃的消息数
+//
+// https://prometheus.io/docs/instrumenting/exposition_formats/
+type PrometheusObserver struct {
+	buckets []float64
+
+	mu         sync.Mutex
+	counters   map[string]map[string]float64
+	histograms map[string]map[string]*histogram
+}
+
+// NewPrometheusObserver buckets 为空时使用 DefaultPrometheusBuckets
+func NewPrometheusObserver(buckets ...float64) *PrometheusObserver {
+	if len(buckets) == 0 {
+		buckets = DefaultPrometheusBuckets
+	}
+	buckets = append([]float64(nil), buckets...)
+	sort.Float64s(buckets)
+
+	return &PrometheusObserver{
+		buckets:    buckets,
+		counters:   make(map[string]map[string]float64),
+		histograms: make(map[string]map[string]*histogram),
+	}
+}
+
+type histogram struct {
+	counts []uint64
+	sum    float64
+	count  uint64
+}
+
+type promMetric struct {
+	name, help, typ string
+}
+
+var (
+	promLimiterWait     = promMetric{"feishu_bot_limiter_wait_seconds", "Time spent waiting for the rate limiter.", "histogram"}
+	promRequests        = promMetric{"feishu_bot_requests_total", "Number of webhook requests sent.", "counter"}
+	promRequestBytes    = promMetric{"feishu_bot_request_bytes_total", "Bytes of webhook request bodies sent.", "counter"}
+	promResponses       = promMetric{"feishu_bot_responses_total", "Number of webhook responses by Feishu code and HTTP status.", "counter"}
+	promResponseLatency = promMetric{"feishu_bot_response_latency_seconds", "Latency of webhook requests.", "histogram"}
+	promRetries         = promMetric{"feishu_bot_retries_total", "Number of retries.", "counter"}
+	promDrops           = promMetric{"feishu_bot_drops_total", "Number of messages dropped after the last attempt.", "counter"}
+
+	promMetrics = []promMetric{promLimiterWait, promRequests, promRequestBytes, promResponses, promResponseLatency, promRetries, promDrops}
+)
+
+func (o *PrometheusObserver) LimiterWaitStart(context.Context, LimiterWaitEvent) {}
+
+func (o *PrometheusObserver) LimiterWaitEnd(_ context.Context, e LimiterWaitEvent) {
+	o.observe(promLimiterWait, promLabels("webhook", e.Webhook), e.Duration.Seconds())
+}
+
+func (o *PrometheusObserver) RequestSent(_ context.Context, e RequestEvent) {
+	labels := promLabels("webhook", e.Webhook, "msg_type", e.MsgType)
+	o.add(promRequests, labels, 1)
+	o.add(promRequestBytes, labels, float64(e.PayloadBytes))
+}
+
+func (o *PrometheusObserver) ResponseReceived(_ context.Context, e ResponseEvent) {
+	o.add(promResponses, promLabels("webhook", e.Webhook, "code", strconv.Itoa(e.Code), "status", strconv.Itoa(e.StatusCode)), 1)
+	o.observe(promResponseLatency, promLabels("webhook", e.Webhook), e.Latency.Seconds())
+}
+
+func (o *PrometheusObserver) Retry(_ context.Context, e RetryEvent) {
+	o.add(promRetries, promLabels("webhook", e.Webhook), 1)
+}
+
+func (o *PrometheusObserver) Drop(_ context.Context, e DropEvent) {
+	o.add(promDrops, promLabels("webhook", e.Webhook), 1)
+}
+
+func (o *PrometheusObserver) add(m promMetric, labels string, v float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	series, ok := o.counters[m.name]
+	if !ok {
+		series = make(map[string]float64)
+		o.counters[m.name] = series
+	}
+	series[labels] += v
+}
+
+func (o *PrometheusObserver) observe(m promMetric, labels string, v float64) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	series, ok := o.histograms[m.name]
+	if !ok {
+		series = make(map[string]*histogram)
+		o.histograms[m.name] = series
+	}
+	h, ok := series[labels]
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(o.buckets))}
+		series[labels] = h
+	}
+
+	for i, upper := range o.buckets {
+		if v <= upper {
+			h.counts[i]++
+		}
+	}
+	h.sum += v
+	h.count++
+}
+
+// ServeHTTP 以 Prometheus 文本格式输出指标
+func (o *PrometheusObserver) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	_ = o.WriteText(w)
+}
+
+// WriteText 以 Prometheus 文本格式输出指标
+func (o *PrometheusObserver) WriteText(w io.Writer) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	bw := bufio.NewWriter(w)
+	for _, m := range promMetrics {
+		switch m.typ {
+		case "counter":
+			series := o.counters[m.name]
+			if len(series) == 0 {
+				continue
+			}
+			fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.typ)
+			for _, labels := range sortedKeys(series) {
+				fmt.Fprintf(bw, "%s{%s} %s\n", m.name, labels, formatPromValue(series[labels]))
+			}
+		case "histogram":
+			series := o.histograms[m.name]
+			if len(series) == 0 {
+				continue
+			}
+			fmt.Fprintf(bw, "# HELP %s %s\n# TYPE %s %s\n", m.name, m.help, m.name, m.typ)
+			for _, labels := range sortedKeys(series) {
+				h := series[labels]
+				for i, upper := range o.buckets {
+					fmt.Fprintf(bw, "%s_bucket{%s,le=\"%s\"} %d\n", m.name, labels, formatPromValue(upper), h.counts[i])
+				}
+				fmt.Fprintf(bw, "%s_bucket{%s,le=\"+Inf\"} %d\n", m.name, labels, h.count)
+				fmt.Fprintf(bw, "%s_sum{%s} %s\n", m.name, labels, formatPromValue(h.sum))
+				fmt.Fprintf(bw, "%s_count{%s} %d\n", m.name, labels, h.count)
+			}
+		}
+	}
+	return bw.Flush()
+}
+
+var _promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// promLabels 按 name、value 交替传入，生成 name="value",... 形式的标签
+func promLabels(kv ...string) string {
+	var sb strings.Builder
+	for i := 0; i+1 < len(kv); i += 2 {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(kv[i])
+		sb.WriteString(`="`)
+		sb.WriteString(_promLabelEscaper.Replace(kv[i+1]))
+		sb.WriteByte('"')
+	}
+	return sb.String()
+}
+
+func formatPromValue(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
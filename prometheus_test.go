@@ -0,0 +1,72 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPrometheusObserver(t *testing.T) {
+	o := NewPrometheusObserver(0.1, 1)
+
+	ctx := context.Background()
+	o.LimiterWaitEnd(ctx, LimiterWaitEvent{Webhook: "0123****cdef", Duration: 500 * time.Millisecond})
+	o.RequestSent(ctx, RequestEvent{Webhook: "0123****cdef", MsgType: "text", PayloadBytes: 40, Attempt: 1})
+	o.RequestSent(ctx, RequestEvent{Webhook: "0123****cdef", MsgType: "text", PayloadBytes: 2, Attempt: 2})
+	o.ResponseReceived(ctx, ResponseEvent{Webhook: "0123****cdef", Latency: 50 * time.Millisecond, Code: 9499, StatusCode: 429})
+	o.ResponseReceived(ctx, ResponseEvent{Webhook: "0123****cdef", Latency: 2 * time.Second, StatusCode: 200})
+	o.Retry(ctx, RetryEvent{Webhook: "0123****cdef"})
+	o.Drop(ctx, DropEvent{Webhook: `a"b`})
+
+	srv := httptest.NewServer(o)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	requireNoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	raw, err := io.ReadAll(resp.Body)
+	requireNoError(t, err)
+
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Errorf("Actual content type: %s", ct)
+	}
+
+	want := `# HELP feishu_bot_limiter_wait_seconds Time spent waiting for the rate limiter.
+# TYPE feishu_bot_limiter_wait_seconds histogram
+feishu_bot_limiter_wait_seconds_bucket{webhook="0123****cdef",le="0.1"} 0
+feishu_bot_limiter_wait_seconds_bucket{webhook="0123****cdef",le="1"} 1
+feishu_bot_limiter_wait_seconds_bucket{webhook="0123****cdef",le="+Inf"} 1
+feishu_bot_limiter_wait_seconds_sum{webhook="0123****cdef"} 0.5
+feishu_bot_limiter_wait_seconds_count{webhook="0123****cdef"} 1
+# HELP feishu_bot_requests_total Number of webhook requests sent.
+# TYPE feishu_bot_requests_total counter
+feishu_bot_requests_total{webhook="0123****cdef",msg_type="text"} 2
+# HELP feishu_bot_request_bytes_total Bytes of webhook request bodies sent.
+# TYPE feishu_bot_request_bytes_total counter
+feishu_bot_request_bytes_total{webhook="0123****cdef",msg_type="text"} 42
+# HELP feishu_bot_responses_total Number of webhook responses by Feishu code and HTTP status.
+# TYPE feishu_bot_responses_total counter
+feishu_bot_responses_total{webhook="0123****cdef",code="0",status="200"} 1
+feishu_bot_responses_total{webhook="0123****cdef",code="9499",status="429"} 1
+# HELP feishu_bot_response_latency_seconds Latency of webhook requests.
+# TYPE feishu_bot_response_latency_seconds histogram
+feishu_bot_response_latency_seconds_bucket{webhook="0123****cdef",le="0.1"} 1
+feishu_bot_response_latency_seconds_bucket{webhook="0123****cdef",le="1"} 1
+feishu_bot_response_latency_seconds_bucket{webhook="0123****cdef",le="+Inf"} 2
+feishu_bot_response_latency_seconds_sum{webhook="0123****cdef"} 2.05
+feishu_bot_response_latency_seconds_count{webhook="0123****cdef"} 2
+# HELP feishu_bot_retries_total Number of retries.
+# TYPE feishu_bot_retries_total counter
+feishu_bot_retries_total{webhook="0123****cdef"} 1
+# HELP feishu_bot_drops_total Number of messages dropped after the last attempt.
+# TYPE feishu_bot_drops_total counter
+feishu_bot_drops_total{webhook="a\"b"} 1
+`
+	if got := string(raw); got != want {
+		t.Errorf("Actual:\n%s\nwant:\n%s", got, want)
+	}
+}
@@ -0,0 +1,46 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"time"
+)
+
+// SendResult 发送结果
+//
+// 发送失败时同样会返回，内容为最后一次请求的结果，便于排查问题（例如向飞书提供 LogID）
+type SendResult struct {
+	// Code、Msg、Data 飞书返回的内容，未收到响应时为零值
+	Code int
+	Msg  string
+	Data any
+
+	// StatusCode HTTP 状态码，未收到响应时为 0
+	StatusCode int
+
+	// LogID 响应头 X-Tt-Logid，向飞书反馈问题时需要提供
+	LogID string
+
+	// Attempts 请求次数（包括重试）
+	Attempts int
+
+	// LimiterWait 限流等待的总时长
+	LimiterWait time.Duration
+
+	// Latency 发送的总耗时（包括限流等待、重试间隔）
+	Latency time.Duration
+
+	// PayloadBytes 最后一次请求的请求体字节数
+	PayloadBytes int
+}
+
+type sendResultKey struct{}
+
+// withSendResult 通过 ctx 将 result 传递给 bot.sendMessage，result 为空时不记录
+func withSendResult(ctx context.Context, result *SendResult) context.Context {
+	return context.WithValue(ctx, sendResultKey{}, result)
+}
+
+func sendResultFromContext(ctx context.Context) *SendResult {
+	result, _ := ctx.Value(sendResultKey{}).(*SendResult)
+	return result
+}
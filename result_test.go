@@ -0,0 +1,80 @@
+package feishu_bot_api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestSendMessageWithResult(t *testing.T) {
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		w.Header().Set("X-Tt-Logid", fmt.Sprintf("202401010000000000%02d", n))
+		switch n {
+		case 1:
+			w.WriteHeader(http.StatusInternalServerError)
+		case 2:
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{"k":"v"}}`))
+		default:
+			_, _ = w.Write([]byte(`{"code":19024,"msg":"Key Words Not Found","data":{}}`))
+		}
+	}))
+	defer srv.Close()
+
+	opts := NewBotOptions().
+		SetBaseURL(srv.URL).
+		SetLimiterRegistry(NewLimiterRegistry()).
+		SetRetry(NewRetryPolicy().SetBackoff(time.Millisecond, time.Millisecond))
+	b := NewBot("tmp", opts)
+
+	result, err := b.SendMessageWithResult(context.Background(), textMessage("hi"))
+	requireNoError(t, err)
+	if result.Code != 0 || result.Msg != "success" || result.StatusCode != http.StatusOK {
+		t.Errorf("Actual result: %+v", result)
+	}
+	if data, _ := result.Data.(map[string]any); data["k"] != "v" {
+		t.Errorf("Actual data: %v", result.Data)
+	}
+	if result.LogID != "20240101000000000002" {
+		t.Errorf("Actual log id: %s, want: 20240101000000000002", result.LogID)
+	}
+	if result.Attempts != 2 {
+		t.Errorf("Actual attempts: %d, want: 2", result.Attempts)
+	}
+	if result.PayloadBytes != len(`{"msg_type":"text","content":{"text":"hi"}}`) {
+		t.Errorf("Actual payload bytes: %d", result.PayloadBytes)
+	}
+	if result.Latency <= 0 || result.Latency < result.LimiterWait {
+		t.Errorf("Actual latency: %s, limiter wait: %s", result.Latency, result.LimiterWait)
+	}
+
+	result, err = b.SendMessageWithResult(context.Background(), textMessage("hi"))
+	if !errors.Is(err, ErrKeywordNotMatched) {
+		t.Fatalf("Actual error: %v, want: %v", err, ErrKeywordNotMatched)
+	}
+	if result == nil || result.Code != CodeKeyWordsNotFound || result.LogID != "20240101000000000003" || result.Attempts != 1 {
+		t.Errorf("Actual result: %+v", result)
+	}
+}
+
+func TestSendMessageWithResult_limiterWait(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code":0,"msg":"success","data":{}}`))
+	}))
+	defer srv.Close()
+
+	b := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetLimiterPerSecond(1))
+	requireNoError(t, b.SendText("hi"))
+
+	result, err := b.SendMessageWithResult(context.Background(), textMessage("hi"))
+	requireNoError(t, err)
+	if result.LimiterWait <= 0 {
+		t.Errorf("Actual limiter wait: %s, want: > 0", result.LimiterWait)
+	}
+}
@@ -0,0 +1,117 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/url"
+	"time"
+)
+
+// RetryPolicy 发送失败时的重试策略
+//
+// 每次重试都会重新等待限流、重新生成 timestamp 与 sign
+type RetryPolicy struct {
+	// MaxAttempts 最大尝试次数（包含首次发送），小于等于 1 时不重试
+	MaxAttempts int
+
+	// BaseBackoff 首次重试前的等待时间，之后每次重试翻倍
+	BaseBackoff time.Duration
+
+	// MaxBackoff 重试等待时间的上限，为 0 时不限制
+	MaxBackoff time.Duration
+
+	// Jitter 随机抖动比例，取值范围 0 ~ 1
+	//
+	// 实际等待时间在 [backoff*(1-Jitter), backoff] 之间随机
+	Jitter float64
+
+	// Retryable 判断错误是否可以重试，为空时使用 IsRetryable
+	Retryable func(err error) bool
+}
+
+// NewRetryPolicy 默认的重试策略：最多尝试 3 次，等待时间从 500ms 开始翻倍，最多等待 10s
+func NewRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts: 3,
+		BaseBackoff: 500 * time.Millisecond,
+		MaxBackoff:  10 * time.Second,
+		Jitter:      0.2,
+	}
+}
+
+func (p *RetryPolicy) SetMaxAttempts(n int) *RetryPolicy {
+	p.MaxAttempts = n
+	return p
+}
+
+func (p *RetryPolicy) SetBackoff(base, maxBackoff time.Duration) *RetryPolicy {
+	p.BaseBackoff = base
+	p.MaxBackoff = maxBackoff
+	return p
+}
+
+func (p *RetryPolicy) SetJitter(f float64) *RetryPolicy {
+	p.Jitter = f
+	return p
+}
+
+func (p *RetryPolicy) SetRetryable(f func(err error) bool) *RetryPolicy {
+	p.Retryable = f
+	return p
+}
+
+// shouldRetry 第 attempt 次发送失败后，是否需要继续重试
+func (p *RetryPolicy) shouldRetry(attempt int, err error) bool {
+	if p == nil || attempt >= p.MaxAttempts {
+		return false
+	}
+
+	if f := p.Retryable; f != nil {
+		return f(err)
+	}
+	return IsRetryable(err)
+}
+
+// backoff 第 attempt 次发送失败后，重试前需要等待的时间
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.BaseBackoff
+	for i := 1; i < attempt && d > 0; i++ {
+		if d > math.MaxInt64/2 {
+			// 继续翻倍会溢出
+			d = math.MaxInt64
+			break
+		}
+		d *= 2
+		if p.MaxBackoff > 0 && d >= p.MaxBackoff {
+			break
+		}
+	}
+	if p.MaxBackoff > 0 && d > p.MaxBackoff {
+		d = p.MaxBackoff
+	}
+
+	if j := min(max(p.Jitter, 0), 1); j > 0 && d > 0 {
+		d -= time.Duration(rand.Float64() * j * float64(d))
+	}
+	return d
+}
+
+// IsRetryable 默认的可重试判断
+//   - 网络错误（包括 HTTP 请求超时）：可以重试
+//   - HTTP 5xx：可以重试
+//   - 触发频率限制（ErrRateLimited）：可以重试
+//   - 其他错误（如签名校验失败、关键词不匹配、ctx 被取消）：不重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || errors.Is(apiErr, ErrRateLimited)
+	}
+
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,126 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	p := &RetryPolicy{MaxAttempts: 10, BaseBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 100 * time.Millisecond},
+		{attempt: 2, want: 200 * time.Millisecond},
+		{attempt: 3, want: 400 * time.Millisecond},
+		{attempt: 4, want: 800 * time.Millisecond},
+		{attempt: 5, want: time.Second},
+		{attempt: 9, want: time.Second},
+	}
+	for _, tt := range tests {
+		if got := p.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %s, want %s", tt.attempt, got, tt.want)
+		}
+	}
+
+	p.Jitter = 0.5
+	for i := 0; i < 100; i++ {
+		if got := p.backoff(1); got < 50*time.Millisecond || got > 100*time.Millisecond {
+			t.Fatalf("backoff(1) with jitter = %s, want [50ms, 100ms]", got)
+		}
+	}
+
+	// MaxBackoff 为 0 时不限制，多次翻倍后不能溢出
+	p = &RetryPolicy{BaseBackoff: time.Second}
+	for _, attempt := range []int{35, 70, 1000} {
+		if got, want := p.backoff(attempt), time.Duration(math.MaxInt64); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempt, got, want)
+		}
+	}
+}
+
+func Test_bot_SendMessage_Retry(t *testing.T) {
+	newServer := func(counter *atomic.Int32, fn func(n int32, w http.ResponseWriter, r *http.Request)) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fn(counter.Add(1), w, r)
+		}))
+	}
+	retry := NewRetryPolicy().SetMaxAttempts(3).SetBackoff(time.Millisecond, 10*time.Millisecond)
+
+	t.Run("http_5xx", func(t *testing.T) {
+		var counter atomic.Int32
+		srv := newServer(&counter, func(n int32, w http.ResponseWriter, r *http.Request) {
+			if n < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"code":0,"msg":"success"}`))
+		})
+		defer srv.Close()
+
+		b := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetSecretKey("secret").SetRetry(retry))
+		requireNoError(t, b.SendText("hi"))
+		if n := counter.Load(); n != 3 {
+			t.Errorf("Actual attempts: %d, want: 3", n)
+		}
+	})
+
+	t.Run("rate_limited_exhausted", func(t *testing.T) {
+		var counter atomic.Int32
+		srv := newServer(&counter, func(n int32, w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"code":9499,"msg":"too many request"}`))
+		})
+		defer srv.Close()
+
+		b := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetRetry(retry))
+		if err := b.SendText("hi"); !errors.Is(err, ErrRateLimited) {
+			t.Fatalf("Actual error: %v, want: %v", err, ErrRateLimited)
+		}
+		if n := counter.Load(); n != 3 {
+			t.Errorf("Actual attempts: %d, want: 3", n)
+		}
+	})
+
+	t.Run("permanent", func(t *testing.T) {
+		var counter atomic.Int32
+		srv := newServer(&counter, func(n int32, w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"code":19021,"msg":"sign match fail or timestamp is not within one hour from current time"}`))
+		})
+		defer srv.Close()
+
+		b := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetSecretKey("secret").SetRetry(retry))
+		if err := b.SendText("hi"); !errors.Is(err, ErrSignatureMismatch) {
+			t.Fatalf("Actual error: %v, want: %v", err, ErrSignatureMismatch)
+		}
+		if n := counter.Load(); n != 1 {
+			t.Errorf("Actual attempts: %d, want: 1", n)
+		}
+	})
+
+	t.Run("network", func(t *testing.T) {
+		srv := httptest.NewServer(http.NotFoundHandler())
+		srv.Close()
+
+		var attempts int
+		policy := *retry
+		policy.Retryable = func(err error) bool {
+			attempts++
+			return IsRetryable(err)
+		}
+
+		b := NewBot("tmp", NewBotOptions().SetBaseURL(srv.URL).SetLimiterRegistry(NewLimiterRegistry()).SetRetry(&policy))
+		if err := b.SendText("hi"); err == nil {
+			t.Fatal("Expected an error")
+		}
+		if attempts != 2 {
+			t.Errorf("Actual retries: %d, want: 2", attempts)
+		}
+	})
+}
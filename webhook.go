@@ -0,0 +1,93 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Region 飞书、Lark 对应不同的开放平台域名，webhook 不能混用
+type Region string
+
+const (
+	// RegionFeishu 飞书（中国）https://open.feishu.cn
+	RegionFeishu Region = "feishu"
+	// RegionLark Lark（国际）https://open.larksuite.com
+	RegionLark Region = "lark"
+)
+
+const feishuBaseURL = "https://open.feishu.cn"
+
+// ErrInvalidWebhook webhook 地址格式错误
+var ErrInvalidWebhook = errors.New("invalid webhook")
+
+var (
+	_webhookPathRegexp  = regexp.MustCompile(`^/open-apis/bot/(v[0-9]+)/hook/([^/]+)/?$`)
+	_webhookTokenRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+)
+
+// Webhook 自定义机器人的 webhook 地址
+//
+// https://open.feishu.cn/open-apis/bot/v2/hook/xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx
+type Webhook struct {
+	// BaseURL 协议与域名，例如 https://open.feishu.cn
+	BaseURL string
+	// Host 域名，例如 open.feishu.cn
+	Host string
+	// Region 根据域名判断，非飞书、Lark 域名（例如私有化部署）时为空
+	Region Region
+	// Version API 版本，例如 v2
+	Version string
+	// Token webhook access token
+	Token string
+}
+
+// ParseWebhook 解析完整的 webhook 地址
+func ParseWebhook(s string) (Webhook, error) {
+	u, err := url.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return Webhook{}, fmt.Errorf("%w: %w", ErrInvalidWebhook, err)
+	}
+
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return Webhook{}, fmt.Errorf("%w: unsupported scheme %q", ErrInvalidWebhook, u.Scheme)
+	}
+	if u.Host == "" {
+		return Webhook{}, fmt.Errorf("%w: missing host", ErrInvalidWebhook)
+	}
+
+	m := _webhookPathRegexp.FindStringSubmatch(u.Path)
+	if m == nil {
+		return Webhook{}, fmt.Errorf("%w: unexpected path %q, want /open-apis/bot/v2/hook/<token>", ErrInvalidWebhook, u.Path)
+	}
+	if !_webhookTokenRegexp.MatchString(m[2]) {
+		return Webhook{}, fmt.Errorf("%w: malformed token %q", ErrInvalidWebhook, maskToken(m[2]))
+	}
+
+	return Webhook{
+		BaseURL: u.Scheme + "://" + u.Host,
+		Host:    u.Host,
+		Region:  regionOf(u.Hostname()),
+		Version: m[1],
+		Token:   m[2],
+	}, nil
+}
+
+func regionOf(host string) Region {
+	host = strings.ToLower(host)
+	switch {
+	case host == "feishu.cn" || strings.HasSuffix(host, ".feishu.cn"):
+		return RegionFeishu
+	case host == "larksuite.com" || strings.HasSuffix(host, ".larksuite.com"):
+		return RegionLark
+	default:
+		return ""
+	}
+}
+
+// String 完整的 webhook 地址
+func (w Webhook) String() string {
+	return w.BaseURL + "/open-apis/bot/" + w.Version + "/hook/" + w.Token
+}
@@ -0,0 +1,149 @@
+package feishu_bot_api
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseWebhook(t *testing.T) {
+	const token = "0d4e8b4a-1c2f-4b7e-9a3d-5f6e7a8b9c0d"
+
+	tests := []struct {
+		name    string
+		webhook string
+		want    Webhook
+		wantErr bool
+	}{
+		{
+			name:    "feishu",
+			webhook: "https://open.feishu.cn/open-apis/bot/v2/hook/" + token,
+			want:    Webhook{BaseURL: "https://open.feishu.cn", Host: "open.feishu.cn", Region: RegionFeishu, Version: "v2", Token: token},
+		},
+		{
+			name:    "lark",
+			webhook: " https://open.larksuite.com/open-apis/bot/v2/hook/" + token + "\n",
+			want:    Webhook{BaseURL: "https://open.larksuite.com", Host: "open.larksuite.com", Region: RegionLark, Version: "v2", Token: token},
+		},
+		{
+			name:    "private_deployment",
+			webhook: "http://127.0.0.1:8080/open-apis/bot/v2/hook/" + token,
+			want:    Webhook{BaseURL: "http://127.0.0.1:8080", Host: "127.0.0.1:8080", Version: "v2", Token: token},
+		},
+		{
+			name:    "token_only",
+			webhook: token,
+			wantErr: true,
+		},
+		{
+			name:    "malformed_token",
+			webhook: "https://open.feishu.cn/open-apis/bot/v2/hook/not-a-token",
+			wantErr: true,
+		},
+		{
+			name:    "mismatched_path",
+			webhook: "https://open.feishu.cn/open-apis/im/v1/messages/" + token,
+			wantErr: true,
+		},
+		{
+			name:    "trailing_segment",
+			webhook: "https://open.feishu.cn/open-apis/bot/v2/hook/" + token + "/extra",
+			wantErr: true,
+		},
+		{
+			name:    "unsupported_scheme",
+			webhook: "ftp://open.feishu.cn/open-apis/bot/v2/hook/" + token,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWebhook(tt.webhook)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidWebhook) {
+					t.Fatalf("Actual error: %v, want: %v", err, ErrInvalidWebhook)
+				}
+				return
+			}
+			requireNoError(t, err)
+			if got != tt.want {
+				t.Errorf("Actual: %+v, want: %+v", got, tt.want)
+			}
+			if s := got.String(); s != tt.want.BaseURL+"/open-apis/bot/v2/hook/"+token {
+				t.Errorf("Actual string: %s", s)
+			}
+		})
+	}
+}
+
+func TestNewBot_webhookHost(t *testing.T) {
+	const token = "0d4e8b4a-1c2f-4b7e-9a3d-5f6e7a8b9c0d"
+
+	tests := []struct {
+		name        string
+		webhook     string
+		baseURL     string
+		wantBaseURL string
+		wantToken   string
+	}{
+		{
+			name:        "lark",
+			webhook:     "https://open.larksuite.com/open-apis/bot/v2/hook/" + token,
+			wantBaseURL: "https://open.larksuite.com",
+			wantToken:   token,
+		},
+		{
+			name:        "base_url_overrides",
+			webhook:     "https://open.larksuite.com/open-apis/bot/v2/hook/" + token,
+			baseURL:     "http://127.0.0.1:8080",
+			wantBaseURL: "http://127.0.0.1:8080",
+			wantToken:   token,
+		},
+		{
+			name:        "token_only",
+			webhook:     token,
+			wantBaseURL: "https://open.feishu.cn",
+			wantToken:   token,
+		},
+		{
+			name:        "legacy",
+			webhook:     "https://open.feishu.cn/open-apis/bot/v2/hook/tmp",
+			wantBaseURL: "https://open.feishu.cn",
+			wantToken:   "tmp",
+		},
+		{
+			name:        "legacy_lark",
+			webhook:     "https://open.larksuite.com/open-apis/bot/v2/hook/abc123",
+			wantBaseURL: "https://open.larksuite.com",
+			wantToken:   "abc123",
+		},
+		{
+			name:        "legacy_private_deployment",
+			webhook:     "http://feishu.example.com:8080/open-apis/bot/v2/hook/abc123/",
+			wantBaseURL: "http://feishu.example.com:8080",
+			wantToken:   "abc123",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			opts := NewBotOptions().SetBaseURL(tt.baseURL).SetLimiterRegistry(NewLimiterRegistry())
+			b := NewBot(tt.webhook, opts).(*bot)
+			if b.baseURL != tt.wantBaseURL {
+				t.Errorf("Actual base url: %s, want: %s", b.baseURL, tt.wantBaseURL)
+			}
+			if b.webhookAccessToken != tt.wantToken {
+				t.Errorf("Actual token: %s, want: %s", b.webhookAccessToken, tt.wantToken)
+			}
+		})
+	}
+
+	t.Run("shared_options", func(t *testing.T) {
+		opts := NewBotOptions().SetLimiterRegistry(NewLimiterRegistry())
+		lark := NewBot("https://open.larksuite.com/open-apis/bot/v2/hook/"+token, opts).(*bot)
+		feishu := NewBot("https://open.feishu.cn/open-apis/bot/v2/hook/"+token, opts).(*bot)
+		if lark.baseURL != "https://open.larksuite.com" || feishu.baseURL != "https://open.feishu.cn" {
+			t.Errorf("Actual base urls: %s, %s", lark.baseURL, feishu.baseURL)
+		}
+	})
+}
@@ -1,5 +1,45 @@
 package feishu_bot_api
 
-import "strings"
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/electricbubble/feishu-bot-api/v2/clock"
+)
 
 var _quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// sleepContext 休眠 d，ctx 被取消时提前返回 ctx.Err()
+func sleepContext(ctx context.Context, c clock.Clock, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	t := c.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C():
+		return nil
+	}
+}
+
+func cloneRawMessage(raw *json.RawMessage) *json.RawMessage {
+	if raw == nil {
+		return nil
+	}
+	cloned := append(json.RawMessage(nil), *raw...)
+	return &cloned
+}
+
+// maskToken 对 webhook access token 脱敏，仅保留首尾各 4 个字符
+func maskToken(token string) string {
+	if len(token) <= 8 {
+		return "****"
+	}
+	return token[:4] + "****" + token[len(token)-4:]
+}